@@ -19,26 +19,107 @@
 package apply
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
 )
 
-func applyInstanceTypeAnnotations(annotations map[string]string, target metav1.Object) (conflicts conflict.Conflicts) {
-	if target.GetAnnotations() == nil {
-		target.SetAnnotations(make(map[string]string))
-	}
+func applyInstanceTypeAnnotations(opts *applyOptions, annotations map[string]string, target metav1.Object) conflict.Conflicts {
+	return applyMetadata(opts, "annotations", annotations, target.GetAnnotations, target.SetAnnotations)
+}
 
-	targetAnnotations := target.GetAnnotations()
-	for key, value := range annotations {
-		if targetValue, exists := targetAnnotations[key]; exists {
-			if targetValue != value {
-				conflicts = append(conflicts, conflict.New("annotations", key))
+func applyInstanceTypeLabels(opts *applyOptions, labels map[string]string, target metav1.Object) conflict.Conflicts {
+	return applyMetadata(opts, "labels", labels, target.GetLabels, target.SetLabels)
+}
+
+// applyMetadata merges values onto the target map returned by get, lazily initializing it with set when nil.
+// With WithMetadataTemplates values are first resolved as templates, returning a conflict for those that can not be resolved.
+// Colliding keys are reported to any collision handler and then handled according to the AnnotationPolicy of the applier.
+func applyMetadata(
+	opts *applyOptions,
+	name string,
+	values map[string]string,
+	get func() map[string]string,
+	set func(map[string]string),
+) (conflicts conflict.Conflicts) {
+	for key, value := range values {
+		value, err := opts.metadataTemplate.resolve(value)
+		if err != nil {
+			conflicts = append(conflicts, conflict.NewWithMessage(
+				fmt.Sprintf("%s %s provided by the instance type has an invalid template: %v", strings.TrimSuffix(name, "s"), key, err),
+				name, key,
+			))
+			continue
+		}
+		targetValues := get()
+		if targetValue, exists := targetValues[key]; exists && targetValue != value {
+			opts.reportCollision(conflict.NewWithMessage(
+				fmt.Sprintf("%s %s with value %q provided by the instance type collides with value %q of the VMI",
+					strings.TrimSuffix(name, "s"), key, value, targetValue),
+				name, key,
+			))
+			switch opts.annotationPolicy {
+			case AnnotationPolicyPreserve:
+				opts.warn(conflict.New(name, key))
+			case AnnotationPolicyOverwrite:
+				targetValues[key] = value
+			default:
+				conflicts = append(conflicts, opts.resolveConflicts(conflict.New(name, key))...)
 			}
 			continue
 		}
-		targetAnnotations[key] = value
+		if targetValues == nil {
+			targetValues = make(map[string]string)
+			set(targetValues)
+		}
+		targetValues[key] = value
 	}
 
 	return conflicts
 }
+
+// metadataTemplateData holds the tokens available to templated annotation and label values, such as {{.Name}},
+// taken from the metadata of the owner of the VMI
+type metadataTemplateData struct {
+	name      string
+	namespace string
+}
+
+// Name is resolved by the {{.Name}} token
+func (d *metadataTemplateData) Name() (string, error) {
+	return nonEmptyToken("Name", d.name)
+}
+
+// Namespace is resolved by the {{.Namespace}} token
+func (d *metadataTemplateData) Namespace() (string, error) {
+	return nonEmptyToken("Namespace", d.namespace)
+}
+
+func nonEmptyToken(token, value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("token %s resolves to an empty value", token)
+	}
+	return value, nil
+}
+
+// resolve resolves the tokens of a templated value. Values are returned unchanged when templates are not enabled
+// or the value contains no template action.
+func (d *metadataTemplateData) resolve(value string) (string, error) {
+	if d == nil || !strings.Contains(value, "{{") {
+		return value, nil
+	}
+	tmpl, err := template.New("value").Parse(value)
+	if err != nil {
+		return "", err
+	}
+	var resolved bytes.Buffer
+	if err := tmpl.Execute(&resolved, d); err != nil {
+		return "", err
+	}
+	return resolved.String(), nil
+}
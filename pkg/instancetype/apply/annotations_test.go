@@ -1,14 +1,18 @@
 package apply_test
 
 import (
+	"fmt"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 	virtv1 "kubevirt.io/api/core/v1"
 	instancetypev1beta1 "kubevirt.io/api/instancetype/v1beta1"
 
 	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
 	"kubevirt.io/kubevirt/pkg/libvmi"
 )
 
@@ -54,5 +58,299 @@ var _ = Describe("Instancetype.Spec.Annotations", func() {
 			Expect(conflicts).To(HaveLen(1))
 			Expect(conflicts[0].String()).To(Equal("annotations.annotation-1"))
 		})
+
+		It("should report conflicts in the same order on every application", func() {
+			applyConflicting := func() conflict.Conflicts {
+				vmi.Annotations = map[string]string{}
+				for i := range 10 {
+					key := fmt.Sprintf("annotation-%d", i)
+					instancetypeSpec.Annotations[key] = "instancetype"
+					vmi.Annotations[key] = "conflict"
+				}
+				return vmiApplier.ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)
+			}
+
+			conflicts := applyConflicting()
+			Expect(conflicts).To(HaveLen(10))
+			Expect(conflicts[0].String()).To(Equal("annotations.annotation-0"))
+			Expect(conflicts[9].String()).To(Equal("annotations.annotation-9"))
+			Expect(applyConflicting().String()).To(Equal(conflicts.String()))
+		})
+	})
+
+	Context("WithAnnotationPolicy", func() {
+		var warnings conflict.Conflicts
+
+		BeforeEach(func() {
+			warnings = nil
+			vmi.Annotations = map[string]string{
+				"annotation-1": "collision",
+			}
+		})
+
+		applyWithPolicy := func(policy apply.AnnotationPolicy) conflict.Conflicts {
+			return apply.NewVMIApplier(
+				apply.WithAnnotationPolicy(policy),
+				apply.WithWarningHandler(func(warning *conflict.Conflict) {
+					warnings = append(warnings, warning)
+				}),
+			).ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)
+		}
+
+		It("should detect conflict with the Conflict policy", func() {
+			Expect(applyWithPolicy(apply.AnnotationPolicyConflict)).To(Equal(
+				conflict.Conflicts{conflict.New("annotations", "annotation-1")}.WithSource(conflict.SourceInstancetype)))
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should keep the VMI annotation and warn with the Preserve policy", func() {
+			Expect(applyWithPolicy(apply.AnnotationPolicyPreserve)).To(Succeed())
+			Expect(vmi.Annotations).To(Equal(map[string]string{
+				"annotation-1": "collision",
+				"annotation-2": "2",
+			}))
+			Expect(warnings).To(Equal(conflict.Conflicts{conflict.New("annotations", "annotation-1")}))
+		})
+
+		It("should replace the VMI annotation with the Overwrite policy", func() {
+			Expect(applyWithPolicy(apply.AnnotationPolicyOverwrite)).To(Succeed())
+			Expect(vmi.Annotations).To(Equal(instancetypeSpec.Annotations))
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	Context("WithCollisionHandler", func() {
+		var collisions conflict.Conflicts
+
+		BeforeEach(func() {
+			collisions = nil
+		})
+
+		applyWithPolicy := func(policy apply.AnnotationPolicy) conflict.Conflicts {
+			return apply.NewVMIApplier(
+				apply.WithAnnotationPolicy(policy),
+				apply.WithCollisionHandler(func(collision *conflict.Conflict) {
+					collisions = append(collisions, collision)
+				}),
+			).ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)
+		}
+
+		DescribeTable("should report colliding annotations with both values", func(policy apply.AnnotationPolicy) {
+			vmi.Annotations = map[string]string{
+				"annotation-1": "collision-1",
+				"annotation-2": "collision-2",
+			}
+
+			applyWithPolicy(policy)
+			Expect(collisions).To(ConsistOf(
+				conflict.NewWithMessage(
+					`annotation annotation-1 with value "1" provided by the instance type collides with value "collision-1" of the VMI`,
+					"annotations", "annotation-1",
+				),
+				conflict.NewWithMessage(
+					`annotation annotation-2 with value "2" provided by the instance type collides with value "collision-2" of the VMI`,
+					"annotations", "annotation-2",
+				),
+			))
+		},
+			Entry("with the default policy", apply.AnnotationPolicy("")),
+			Entry("with the Conflict policy", apply.AnnotationPolicyConflict),
+			Entry("with the Preserve policy", apply.AnnotationPolicyPreserve),
+			Entry("with the Overwrite policy", apply.AnnotationPolicyOverwrite),
+		)
+
+		It("should not report annotations that do not collide", func() {
+			vmi.Annotations = map[string]string{
+				"annotation-1": "1",
+				"annotation-3": "3",
+			}
+
+			Expect(applyWithPolicy(apply.AnnotationPolicyConflict)).To(Succeed())
+			Expect(collisions).To(BeEmpty())
+		})
+	})
+
+	Context("WithMetadataTemplates", func() {
+		var (
+			owner             *virtv1.VirtualMachine
+			templatingApplier func() conflict.Conflicts
+		)
+
+		BeforeEach(func() {
+			owner = &virtv1.VirtualMachine{ObjectMeta: metav1.ObjectMeta{Name: "testvm", Namespace: "testnamespace"}}
+			// The VMI template of a VM usually has no name or namespace of its own
+			vmi.Name = ""
+			vmi.Namespace = ""
+			templatingApplier = func() conflict.Conflicts {
+				return apply.NewVMIApplier(apply.WithMetadataTemplates(owner)).
+					ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)
+			}
+		})
+
+		It("should apply templated values literally without the option", func() {
+			instancetypeSpec.Annotations = map[string]string{"literal": "{{.Name}}", "malformed": "{{.Name"}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Annotations).To(Equal(instancetypeSpec.Annotations))
+		})
+
+		It("should resolve tokens from the metadata of the owner", func() {
+			instancetypeSpec.Annotations = map[string]string{
+				"templated": "{{.Namespace}}/{{.Name}}",
+				"literal":   "value",
+			}
+
+			Expect(templatingApplier()).To(Succeed())
+			Expect(vmi.Annotations).To(Equal(map[string]string{
+				"templated": "testnamespace/testvm",
+				"literal":   "value",
+			}))
+		})
+
+		It("should compare the resolved value with that of the VMI", func() {
+			instancetypeSpec.Annotations = map[string]string{"templated": "{{.Name}}"}
+			vmi.Annotations = map[string]string{"templated": "testvm"}
+
+			Expect(templatingApplier()).To(Succeed())
+			Expect(vmi.Annotations).To(HaveKeyWithValue("templated", "testvm"))
+		})
+
+		It("should resolve tokens in labels", func() {
+			result := apply.NewVMIApplier(apply.WithMetadataTemplates(owner)).ApplyToVMIWithResult(
+				field, &apply.AppliedSource{Labels: map[string]string{"label": "{{.Name}}"}}, instancetypeSpec, nil, nil,
+				&vmi.Spec, &vmi.ObjectMeta)
+			Expect(result.Conflicts).To(BeEmpty())
+			Expect(vmi.Labels).To(HaveKeyWithValue("label", "testvm"))
+		})
+
+		DescribeTable("should return a conflict for a value that can not be resolved", func(value, expectedError string) {
+			owner.Namespace = ""
+			instancetypeSpec.Annotations = map[string]string{"templated": value}
+
+			conflicts := templatingApplier()
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].Path.String()).To(Equal("annotations.templated"))
+			Expect(conflicts[0].Message).To(HavePrefix("annotation templated provided by the instance type has an invalid template: "))
+			Expect(conflicts[0].Message).To(ContainSubstring(expectedError))
+			Expect(vmi.Annotations).ToNot(HaveKey("templated"))
+		},
+			Entry("that is malformed", "{{.Name", "unclosed action"),
+			Entry("that references an unknown token", "{{.Unknown}}", "can't evaluate field Unknown"),
+			Entry("with a token resolving to an empty value", "{{.Namespace}}", "token Namespace resolves to an empty value"),
+		)
+	})
+
+	It("should not initialize VMI annotations when no annotations are applied", func() {
+		vmi.Annotations = nil
+		Expect(vmiApplier.ApplyToVMI(field, &instancetypev1beta1.VirtualMachineInstancetypeSpec{}, nil, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Annotations).To(BeNil())
+	})
+})
+
+var _ = Describe("Instancetype labels", func() {
+	var (
+		vmi              *virtv1.VirtualMachineInstance
+		instancetypeSpec *instancetypev1beta1.VirtualMachineInstancetypeSpec
+		labels           map[string]string
+
+		field = k8sfield.NewPath("spec", "template", "spec")
+	)
+
+	BeforeEach(func() {
+		vmi = libvmi.New()
+		instancetypeSpec = &instancetypev1beta1.VirtualMachineInstancetypeSpec{}
+		labels = map[string]string{
+			"label-1": "1",
+			"label-2": "2",
+		}
+	})
+
+	applyLabels := func(labels map[string]string, opts ...apply.Option) conflict.Conflicts {
+		instancetypeSource := &apply.AppliedSource{Kind: "VirtualMachineInstancetype", Name: "instancetype", Labels: labels}
+		result := apply.NewVMIApplier(opts...).ApplyToVMIWithResult(
+			field, instancetypeSource, instancetypeSpec, nil, nil, &vmi.Spec, &vmi.ObjectMeta)
+		return result.Conflicts
+	}
+
+	It("should apply to VMI without labels", func() {
+		vmi.Labels = nil
+		Expect(applyLabels(labels)).To(BeEmpty())
+		Expect(vmi.Labels).To(Equal(labels))
+	})
+
+	It("should merge with existing VMI labels", func() {
+		vmi.Labels = map[string]string{
+			"label-1": "1",
+			"vmi":     "label",
+		}
+		Expect(applyLabels(labels)).To(BeEmpty())
+		Expect(vmi.Labels).To(Equal(map[string]string{
+			"label-1": "1",
+			"label-2": "2",
+			"vmi":     "label",
+		}))
+	})
+
+	It("should not touch VMI labels when none are provided", func() {
+		vmi.Labels = nil
+		Expect(applyLabels(nil)).To(BeEmpty())
+		Expect(vmi.Labels).To(BeNil())
+	})
+
+	It("should not apply labels through ApplyToVMI as no instancetype source is provided", func() {
+		vmi.Labels = nil
+		Expect(apply.NewVMIApplier().ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Labels).To(BeNil())
+	})
+
+	It("should apply the labels provided with each call", func() {
+		vmiApplier := apply.NewVMIApplier()
+		otherVMI := vmi.DeepCopy()
+
+		result := vmiApplier.ApplyToVMIWithResult(
+			field, &apply.AppliedSource{Labels: labels}, instancetypeSpec, nil, nil, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(result.Conflicts).To(BeEmpty())
+		otherLabels := map[string]string{"other": "label"}
+		result = vmiApplier.ApplyToVMIWithResult(
+			field, &apply.AppliedSource{Labels: otherLabels}, instancetypeSpec, nil, nil, &otherVMI.Spec, &otherVMI.ObjectMeta)
+		Expect(result.Conflicts).To(BeEmpty())
+
+		Expect(vmi.Labels).To(Equal(labels))
+		Expect(otherVMI.Labels).To(Equal(otherLabels))
+	})
+
+	DescribeTable("should handle label collisions", func(policy apply.AnnotationPolicy, expectedLabel string, expectConflict bool) {
+		vmi.Labels = map[string]string{
+			"label-1": "collision",
+		}
+		conflicts := applyLabels(labels, apply.WithAnnotationPolicy(policy))
+		if expectConflict {
+			Expect(conflicts).To(Equal(conflict.Conflicts{conflict.New("labels", "label-1")}.WithSource(conflict.SourceInstancetype)))
+			return
+		}
+		Expect(conflicts).To(BeEmpty())
+		Expect(vmi.Labels).To(HaveKeyWithValue("label-1", expectedLabel))
+		Expect(vmi.Labels).To(HaveKeyWithValue("label-2", "2"))
+	},
+		Entry("with the default policy", apply.AnnotationPolicy(""), "", true),
+		Entry("with the Conflict policy", apply.AnnotationPolicyConflict, "", true),
+		Entry("with the Preserve policy", apply.AnnotationPolicyPreserve, "collision", false),
+		Entry("with the Overwrite policy", apply.AnnotationPolicyOverwrite, "1", false),
+	)
+
+	It("should report colliding labels to the collision handler", func() {
+		vmi.Labels = map[string]string{
+			"label-1": "collision",
+		}
+		var collisions conflict.Conflicts
+		Expect(applyLabels(labels,
+			apply.WithAnnotationPolicy(apply.AnnotationPolicyOverwrite),
+			apply.WithCollisionHandler(func(collision *conflict.Conflict) {
+				collisions = append(collisions, collision)
+			}),
+		)).To(BeEmpty())
+		Expect(collisions).To(Equal(conflict.Conflicts{conflict.NewWithMessage(
+			`label label-1 with value "1" provided by the instance type collides with value "collision" of the VMI`, "labels", "label-1",
+		)}))
 	})
 })
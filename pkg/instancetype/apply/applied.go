@@ -0,0 +1,144 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+package apply
+
+import (
+	"reflect"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+)
+
+// AppliedField records a single VMI field set by the applier along with the value it was set to
+type AppliedField struct {
+	Field *k8sfield.Path
+	Value interface{}
+}
+
+// ApplyToVMIWithAppliedFields behaves exactly as ApplyToVMI while also returning each field of the VMI mutated by the
+// instancetype and preference. Fields of vmiSpec are relative to the provided field path, with annotations and labels
+// being recorded relative to the annotations and labels paths as with any conflicts.
+func (a *vmiApplier) ApplyToVMIWithAppliedFields(
+	field *k8sfield.Path,
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+	vmiMetadata *metav1.ObjectMeta,
+) (conflict.Conflicts, []AppliedField, error) {
+	originalSpec := vmiSpec.DeepCopy()
+	originalMetadata := vmiMetadata.DeepCopy()
+
+	conflicts := a.ApplyToVMI(field, instancetypeSpec, preferenceSpec, vmiSpec, vmiMetadata)
+
+	appliedFields, err := diffVMI(field, originalSpec, originalMetadata, vmiSpec, vmiMetadata)
+	if err != nil {
+		return conflicts, nil, err
+	}
+
+	return conflicts, appliedFields, nil
+}
+
+// diffVMI records each field of the VMI spec and metadata changed between the original and current copies
+func diffVMI(
+	field *k8sfield.Path,
+	originalSpec *virtv1.VirtualMachineInstanceSpec,
+	originalMetadata *metav1.ObjectMeta,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+	vmiMetadata *metav1.ObjectMeta,
+) ([]AppliedField, error) {
+	appliedFields, err := diffObjects(field, originalSpec, vmiSpec)
+	if err != nil {
+		return nil, err
+	}
+	appliedFields = append(appliedFields, diffValues(
+		k8sfield.NewPath("annotations"), toInterfaceMap(originalMetadata.GetAnnotations()), toInterfaceMap(vmiMetadata.GetAnnotations()))...)
+	appliedFields = append(appliedFields, diffValues(
+		k8sfield.NewPath("labels"), toInterfaceMap(originalMetadata.GetLabels()), toInterfaceMap(vmiMetadata.GetLabels()))...)
+	return appliedFields, nil
+}
+
+func diffObjects(field *k8sfield.Path, before, after interface{}) ([]AppliedField, error) {
+	beforeObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(before)
+	if err != nil {
+		return nil, err
+	}
+	afterObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(after)
+	if err != nil {
+		return nil, err
+	}
+	return diffValues(field, beforeObj, afterObj), nil
+}
+
+// diffValues walks two unstructured values recording the path and new value of anything added or changed
+func diffValues(field *k8sfield.Path, before, after interface{}) []AppliedField {
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+
+	switch afterValue := after.(type) {
+	case map[string]interface{}:
+		// Newly created objects are walked as if empty so that each field set within them is recorded
+		beforeValue, ok := before.(map[string]interface{})
+		if !ok && before != nil {
+			break
+		}
+		var appliedFields []AppliedField
+		keys := make([]string, 0, len(afterValue))
+		for key := range afterValue {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			appliedFields = append(appliedFields, diffValues(field.Child(key), beforeValue[key], afterValue[key])...)
+		}
+		return appliedFields
+	case []interface{}:
+		// Lists of differing lengths are recorded as a whole as elements may have been added
+		beforeValue, ok := before.([]interface{})
+		if !ok || len(beforeValue) != len(afterValue) {
+			break
+		}
+		var appliedFields []AppliedField
+		for i := range afterValue {
+			appliedFields = append(appliedFields, diffValues(field.Index(i), beforeValue[i], afterValue[i])...)
+		}
+		return appliedFields
+	}
+
+	if after == nil {
+		return nil
+	}
+
+	return []AppliedField{{Field: field, Value: after}}
+}
+
+func toInterfaceMap(m map[string]string) map[string]interface{} {
+	converted := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		converted[key] = value
+	}
+	return converted
+}
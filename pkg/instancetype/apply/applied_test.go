@@ -0,0 +1,104 @@
+package apply_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/libvmi"
+)
+
+var _ = Describe("ApplyToVMIWithAppliedFields", func() {
+	var (
+		vmi              *virtv1.VirtualMachineInstance
+		instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec
+		preferenceSpec   *v1beta1.VirtualMachinePreferenceSpec
+
+		vmiApplier = apply.NewVMIApplier()
+		field      = k8sfield.NewPath("spec", "template", "spec")
+	)
+
+	BeforeEach(func() {
+		vmi = libvmi.New()
+
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			CPU: v1beta1.CPUInstancetype{
+				Guest: uint32(2),
+			},
+			Memory: v1beta1.MemoryInstancetype{
+				Guest: resource.MustParse("512Mi"),
+			},
+			GPUs: []virtv1.GPU{{
+				Name:       "gpu1",
+				DeviceName: "vendor.com/gpu_name",
+			}},
+			HostDevices: []virtv1.HostDevice{{
+				Name:       "hostdevice1",
+				DeviceName: "vendor.com/hostdevice_name",
+			}},
+			Annotations: map[string]string{
+				"annotation": "value",
+			},
+		}
+
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			Machine: &v1beta1.MachinePreferences{
+				PreferredMachineType: "q35",
+			},
+		}
+	})
+
+	It("should record each field applied to the VMI", func() {
+		conflicts, appliedFields, err := vmiApplier.ApplyToVMIWithAppliedFields(
+			field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conflicts).To(BeEmpty())
+
+		Expect(appliedFields).To(ContainElements(
+			apply.AppliedField{Field: field.Child("domain", "cpu", "sockets"), Value: uint64(2)},
+			apply.AppliedField{Field: field.Child("domain", "cpu", "cores"), Value: uint64(1)},
+			apply.AppliedField{Field: field.Child("domain", "cpu", "threads"), Value: uint64(1)},
+			apply.AppliedField{Field: field.Child("domain", "memory", "guest"), Value: "512Mi"},
+			apply.AppliedField{Field: field.Child("domain", "devices", "gpus"), Value: []interface{}{
+				map[string]interface{}{"name": "gpu1", "deviceName": "vendor.com/gpu_name"},
+			}},
+			apply.AppliedField{Field: field.Child("domain", "devices", "hostDevices"), Value: []interface{}{
+				map[string]interface{}{"name": "hostdevice1", "deviceName": "vendor.com/hostdevice_name"},
+			}},
+			apply.AppliedField{Field: field.Child("domain", "machine", "type"), Value: "q35"},
+			apply.AppliedField{Field: k8sfield.NewPath("annotations", "annotation"), Value: "value"},
+		))
+	})
+
+	It("should not record fields left untouched", func() {
+		_, appliedFields, err := vmiApplier.ApplyToVMIWithAppliedFields(field, nil, nil, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(appliedFields).To(BeEmpty())
+	})
+
+	It("should record fields set within an existing list element", func() {
+		vmi.Spec.Domain.Devices.Disks = []virtv1.Disk{{
+			Name: "disk",
+			DiskDevice: virtv1.DiskDevice{
+				Disk: &virtv1.DiskTarget{},
+			},
+		}}
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			Devices: &v1beta1.DevicePreferences{
+				PreferredDiskBus: virtv1.DiskBusVirtio,
+			},
+		}
+
+		_, appliedFields, err := vmiApplier.ApplyToVMIWithAppliedFields(field, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(appliedFields).To(ConsistOf(
+			apply.AppliedField{Field: field.Child("domain", "devices", "disks").Index(0).Child("disk", "bus"), Value: "virtio"},
+		))
+	})
+})
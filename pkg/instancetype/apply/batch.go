@@ -0,0 +1,53 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+package apply
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+)
+
+// VMIItem is a single named VMI spec and metadata to apply an instancetype and preference to with ApplyToVMIs
+type VMIItem struct {
+	Name     string
+	Spec     *virtv1.VirtualMachineInstanceSpec
+	Metadata *metav1.ObjectMeta
+}
+
+// ApplyToVMIs applies the instancetype and preference to each VMI in turn, returning the conflicts of each VMI keyed by name.
+// A VMI with conflicts does not stop the remaining VMIs from being applied to, and VMIs applied cleanly are not included.
+func (a *vmiApplier) ApplyToVMIs(
+	field *k8sfield.Path,
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
+	vmis []*VMIItem,
+) map[string]conflict.Conflicts {
+	conflictsByName := map[string]conflict.Conflicts{}
+	for _, vmi := range vmis {
+		if conflicts := a.ApplyToVMI(field, instancetypeSpec, preferenceSpec, vmi.Spec, vmi.Metadata); len(conflicts) > 0 {
+			conflictsByName[vmi.Name] = append(conflictsByName[vmi.Name], conflicts...)
+		}
+	}
+	return conflictsByName
+}
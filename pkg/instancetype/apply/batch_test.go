@@ -0,0 +1,93 @@
+package apply_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+	"kubevirt.io/kubevirt/pkg/libvmi"
+)
+
+var _ = Describe("ApplyToVMIs", func() {
+	var (
+		instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec
+		preferenceSpec   *v1beta1.VirtualMachinePreferenceSpec
+
+		vmiApplier = apply.NewVMIApplier()
+		field      = k8sfield.NewPath("spec", "template", "spec")
+	)
+
+	newVMIItem := func(name string, opts ...libvmi.Option) (*virtv1.VirtualMachineInstance, *apply.VMIItem) {
+		vmi := libvmi.New(append([]libvmi.Option{libvmi.WithName(name)}, opts...)...)
+		return vmi, &apply.VMIItem{
+			Name:     name,
+			Spec:     &vmi.Spec,
+			Metadata: &vmi.ObjectMeta,
+		}
+	}
+
+	BeforeEach(func() {
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			CPU: v1beta1.CPUInstancetype{
+				Guest: uint32(2),
+			},
+			Memory: v1beta1.MemoryInstancetype{
+				Guest: resource.MustParse("512Mi"),
+			},
+		}
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			Machine: &v1beta1.MachinePreferences{
+				PreferredMachineType: "q35",
+			},
+		}
+	})
+
+	It("should apply to each VMI without returning any conflicts", func() {
+		first, firstItem := newVMIItem("first")
+		second, secondItem := newVMIItem("second")
+
+		Expect(vmiApplier.ApplyToVMIs(field, instancetypeSpec, preferenceSpec, []*apply.VMIItem{firstItem, secondItem})).To(BeEmpty())
+
+		for _, vmi := range []*virtv1.VirtualMachineInstance{first, second} {
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(instancetypeSpec.CPU.Guest))
+			Expect(vmi.Spec.Domain.Memory.Guest).To(HaveValue(Equal(instancetypeSpec.Memory.Guest)))
+			Expect(vmi.Spec.Domain.Machine.Type).To(Equal("q35"))
+		}
+	})
+
+	It("should return the conflicts of each conflicting VMI and keep applying to the others", func() {
+		clean, cleanItem := newVMIItem("clean")
+		_, cpuItem := newVMIItem("conflicting-cpu", libvmi.WithCPUCount(4, 1, 1))
+		_, memoryItem := newVMIItem("conflicting-memory", libvmi.WithGuestMemory("1Gi"))
+		last, lastItem := newVMIItem("last")
+
+		conflicts := vmiApplier.ApplyToVMIs(field, instancetypeSpec, preferenceSpec,
+			[]*apply.VMIItem{cleanItem, cpuItem, memoryItem, lastItem})
+		Expect(conflicts).To(Equal(map[string]conflict.Conflicts{
+			"conflicting-cpu": conflict.Conflicts{
+				conflict.New("spec", "template", "spec", "domain", "cpu", "cores"),
+				conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
+				conflict.New("spec", "template", "spec", "domain", "cpu", "threads"),
+			}.WithSource(conflict.SourceInstancetype),
+			"conflicting-memory": conflict.Conflicts{
+				conflict.New("spec", "template", "spec", "domain", "memory"),
+			}.WithSource(conflict.SourceInstancetype),
+		}))
+
+		for _, vmi := range []*virtv1.VirtualMachineInstance{clean, last} {
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(instancetypeSpec.CPU.Guest))
+			Expect(vmi.Spec.Domain.Machine.Type).To(Equal("q35"))
+		}
+	})
+
+	It("should return no conflicts for an empty list", func() {
+		Expect(vmiApplier.ApplyToVMIs(field, instancetypeSpec, preferenceSpec, nil)).To(BeEmpty())
+	})
+})
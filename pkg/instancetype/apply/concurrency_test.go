@@ -0,0 +1,186 @@
+package apply_test
+
+import (
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+	"kubevirt.io/kubevirt/pkg/libvmi"
+	"kubevirt.io/kubevirt/pkg/pointer"
+)
+
+// These tests are most useful when run with the race detector enabled
+var _ = Describe("Concurrent use of a single VMIApplier", func() {
+	const numVMIs = 50
+
+	var (
+		instancetypeSource *apply.AppliedSource
+		instancetypeSpec   *v1beta1.VirtualMachineInstancetypeSpec
+		preferenceSpec     *v1beta1.VirtualMachinePreferenceSpec
+
+		field = k8sfield.NewPath("spec", "template", "spec")
+	)
+
+	BeforeEach(func() {
+		instancetypeSource = &apply.AppliedSource{Labels: map[string]string{"label": "value"}}
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			CPU: v1beta1.CPUInstancetype{
+				Guest: uint32(4),
+				Model: pointer.P("host-passthrough"),
+			},
+			Memory: v1beta1.MemoryInstancetype{
+				Guest:             resource.MustParse("1Gi"),
+				OvercommitPercent: 10,
+			},
+			NodeSelector: map[string]string{"key": "value"},
+			GPUs:         []virtv1.GPU{{Name: "gpu", DeviceName: "vendor.com/gpu"}},
+			Annotations:  map[string]string{"annotation": "value"},
+		}
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			CPU: &v1beta1.CPUPreferences{
+				PreferredCPUTopology: pointer.P(v1beta1.Spread),
+				PreferredCPUFeatures: []virtv1.CPUFeature{{Name: "feature", Policy: "require"}},
+			},
+			Devices: &v1beta1.DevicePreferences{
+				PreferredDiskBus:        virtv1.DiskBusVirtio,
+				PreferredInterfaceModel: virtv1.VirtIO,
+				PreferredRng:            &virtv1.Rng{},
+			},
+			Machine: &v1beta1.MachinePreferences{
+				PreferredMachineType: "q35",
+			},
+			Annotations: map[string]string{"preference-annotation": "value"},
+		}
+	})
+
+	applyConcurrently := func(opts ...apply.Option) ([]*virtv1.VirtualMachineInstance, []conflict.Conflicts) {
+		vmiApplier := apply.NewVMIApplier(opts...)
+		vmis := make([]*virtv1.VirtualMachineInstance, numVMIs)
+		conflicts := make([]conflict.Conflicts, numVMIs)
+		for i := range vmis {
+			vmis[i] = libvmi.New(
+				libvmi.WithName(fmt.Sprintf("vmi-%d", i)),
+				libvmi.WithContainerDisk("disk", "image"),
+				libvmi.WithInterface(libvmi.InterfaceDeviceWithMasqueradeBinding()),
+				libvmi.WithNetwork(virtv1.DefaultPodNetwork()),
+			)
+		}
+
+		var wg sync.WaitGroup
+		for i := range vmis {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				conflicts[i] = vmiApplier.ApplyToVMIWithResult(
+					field, instancetypeSource, instancetypeSpec, nil, preferenceSpec, &vmis[i].Spec, &vmis[i].ObjectMeta).Conflicts
+			}(i)
+		}
+		wg.Wait()
+		return vmis, conflicts
+	}
+
+	It("should apply the same instancetype and preference to many VMIs", func() {
+		originalInstancetypeSpec := instancetypeSpec.DeepCopy()
+		originalPreferenceSpec := preferenceSpec.DeepCopy()
+
+		vmis, conflicts := applyConcurrently()
+
+		for i, vmi := range vmis {
+			Expect(conflicts[i]).To(BeEmpty())
+			Expect(vmi.Spec).To(Equal(vmis[0].Spec))
+			Expect(vmi.Annotations).To(Equal(vmis[0].Annotations))
+			Expect(vmi.Labels).To(HaveKeyWithValue("label", "value"))
+		}
+		Expect(vmis[0].Spec.Domain.Machine.Type).To(Equal("q35"))
+		Expect(vmis[0].Spec.Domain.Devices.Disks[0].Disk.Bus).To(Equal(virtv1.DiskBusVirtio))
+
+		// Mutating one VMI must not affect the others or the shared specs
+		vmis[0].Spec.Domain.CPU.Features[0].Name = "mutated"
+		vmis[0].Spec.NodeSelector["key"] = "mutated"
+		vmis[0].Spec.Domain.Devices.GPUs[0].Name = "mutated"
+		Expect(vmis[1].Spec.Domain.CPU.Features[0].Name).To(Equal("feature"))
+		Expect(vmis[1].Spec.NodeSelector).To(HaveKeyWithValue("key", "value"))
+		Expect(vmis[1].Spec.Domain.Devices.GPUs[0].Name).To(Equal("gpu"))
+		Expect(instancetypeSpec).To(Equal(originalInstancetypeSpec))
+		Expect(preferenceSpec).To(Equal(originalPreferenceSpec))
+	})
+
+	It("should return the same conflicts for many VMIs", func() {
+		instancetypeSpec.Memory.MaxGuest = pointer.P(resource.MustParse("512Mi"))
+
+		_, conflicts := applyConcurrently()
+
+		for i := range conflicts {
+			Expect(conflicts[i]).To(HaveLen(1))
+			Expect(conflicts[i][0].Error()).To(Equal(
+				"maxGuest memory 512Mi provided by the instance type must be greater than or equal to guest memory 1Gi"))
+		}
+	})
+
+	It("should call the warning handler from each concurrent apply", func() {
+		var (
+			lock     sync.Mutex
+			warnings int
+		)
+		instancetypeSpec.Annotations = nil
+		instancetypeSpec.NodeSelector = map[string]string{"key": "value"}
+
+		vmis := make([]*virtv1.VirtualMachineInstance, numVMIs)
+		vmiApplier := apply.NewVMIApplier(
+			apply.WithVMIOverrides(),
+			apply.WithWarningHandler(func(*conflict.Conflict) {
+				lock.Lock()
+				defer lock.Unlock()
+				warnings++
+			}),
+		)
+		var wg sync.WaitGroup
+		for i := range vmis {
+			vmis[i] = libvmi.New(libvmi.WithNodeSelector("key", "conflict"))
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, nil, &vmis[i].Spec, &vmis[i].ObjectMeta)).To(Succeed())
+			}(i)
+		}
+		wg.Wait()
+		Expect(warnings).To(Equal(numVMIs))
+	})
+
+	It("should warn about guest memory exceeding every node from each concurrent apply", func() {
+		var (
+			lock     sync.Mutex
+			warnings []string
+		)
+		// Unlike a parsed quantity, a new quantity does not yet cache its string representation
+		instancetypeSpec.Memory.Guest = *resource.NewQuantity(1024*1024*1024, resource.BinarySI)
+
+		_, conflicts := applyConcurrently(
+			apply.WithNodeAllocatableMemory(resource.MustParse("512Mi"), resource.MustParse("768Mi")),
+			apply.WithWarningHandler(func(warning *conflict.Conflict) {
+				lock.Lock()
+				defer lock.Unlock()
+				warnings = append(warnings, warning.Error())
+			}),
+		)
+
+		for i := range conflicts {
+			Expect(conflicts[i]).To(BeEmpty())
+		}
+		Expect(warnings).To(HaveLen(numVMIs))
+		Expect(warnings).To(HaveEach(
+			"guest memory 1Gi provided by the instance type exceeds the allocatable memory of every schedulable node, the largest being 768Mi"))
+	})
+})
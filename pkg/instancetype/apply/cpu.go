@@ -20,16 +20,27 @@
 package apply
 
 import (
+	"fmt"
+
 	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 
 	virtv1 "kubevirt.io/api/core/v1"
 	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
 
 	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
 	preferenceApply "kubevirt.io/kubevirt/pkg/instancetype/preference/apply"
+	"kubevirt.io/kubevirt/pkg/instancetype/preference/validation"
 )
 
+// applyCPU applies the CPU of the instancetype, shaped by the CPU preferences of any preference.
+// The instancetype always provides the number of vCPUs while the preference only decides how they are laid out
+// across sockets, cores and threads, so a conflict between the two is only returned when the preferred topology
+// can not lay out exactly the vCPUs provided, such as a spread ratio that does not divide them.
+// Values already provided by the VMI that are identical to those the instancetype would apply are accepted so that
+// applying an instancetype to an already expanded VMI is a no-op.
 func applyCPU(
+	opts *applyOptions,
 	baseConflict *conflict.Conflict,
 	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
 	preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
@@ -40,19 +51,29 @@ func applyCPU(
 	}
 
 	// If we have any conflicts return as there's no need to apply the topology below
-	if conflicts := validateCPU(baseConflict, instancetypeSpec, vmiSpec); len(conflicts) > 0 {
+	if conflicts := opts.resolveConflicts(validateCPU(baseConflict, instancetypeSpec, preferenceSpec, vmiSpec)...); len(conflicts) > 0 {
 		return conflicts
 	}
 
+	if numaConflict := validateNUMA(instancetypeSpec, vmiSpec); numaConflict != nil {
+		return conflict.Conflicts{numaConflict}
+	}
+
+	if isolateConflict := validateIsolateEmulatorThread(instancetypeSpec, vmiSpec); isolateConflict != nil {
+		return conflict.Conflicts{isolateConflict}
+	}
+
+	// When VMI overrides are enabled the conflicts above are only warnings so
+	// each of the following must also avoid replacing any value provided by the VMI.
 	if vmiSpec.Domain.CPU.Model == "" && instancetypeSpec.CPU.Model != nil {
 		vmiSpec.Domain.CPU.Model = *instancetypeSpec.CPU.Model
 	}
 
-	if instancetypeSpec.CPU.DedicatedCPUPlacement != nil {
+	if instancetypeSpec.CPU.DedicatedCPUPlacement != nil && !vmiSpec.Domain.CPU.DedicatedCPUPlacement {
 		vmiSpec.Domain.CPU.DedicatedCPUPlacement = *instancetypeSpec.CPU.DedicatedCPUPlacement
 	}
 
-	if instancetypeSpec.CPU.IsolateEmulatorThread != nil {
+	if instancetypeSpec.CPU.IsolateEmulatorThread != nil && !vmiSpec.Domain.CPU.IsolateEmulatorThread {
 		vmiSpec.Domain.CPU.IsolateEmulatorThread = *instancetypeSpec.CPU.IsolateEmulatorThread
 	}
 
@@ -64,20 +85,153 @@ func applyCPU(
 		vmiSpec.Domain.CPU.Realtime = instancetypeSpec.CPU.Realtime.DeepCopy()
 	}
 
-	if instancetypeSpec.CPU.MaxSockets != nil {
+	if instancetypeSpec.CPU.MaxSockets != nil && vmiSpec.Domain.CPU.MaxSockets == 0 {
 		vmiSpec.Domain.CPU.MaxSockets = *instancetypeSpec.CPU.MaxSockets
 	}
 
-	applyGuestCPUTopology(instancetypeSpec.CPU.Guest, preferenceSpec, vmiSpec)
+	// Validate the topology against maxSockets before applyGuestCPUTopology caps the sockets at it
+	if maxSocketsConflict := validateMaxSockets(instancetypeSpec, preferenceSpec, vmiSpec); maxSocketsConflict != nil {
+		return conflict.Conflicts{maxSocketsConflict}
+	}
+
+	if vmiSpec.Domain.CPU.Sockets == 0 && vmiSpec.Domain.CPU.Cores == 0 && vmiSpec.Domain.CPU.Threads == 0 {
+		// Ensure the vCPUs of the instancetype can be spread exactly using the ratio provided by the preference
+		if instancetypeSpec.CPU.Guest > 1 {
+			if spreadConflict := validation.CheckSpreadCPUTopology(instancetypeSpec, preferenceSpec); spreadConflict != nil {
+				return conflict.Conflicts{spreadConflict}
+			}
+		}
+		applyGuestCPUTopology(instancetypeSpec, preferenceSpec, vmiSpec.Domain.CPU)
+	}
 
 	return nil
 }
 
-func applyGuestCPUTopology(vCPUs uint32, preferenceSpec *v1beta1.VirtualMachinePreferenceSpec, vmiSpec *virtv1.VirtualMachineInstanceSpec) {
+const (
+	instancetypeNUMAGuestMappingPassthroughPath = "instancetype.spec.cpu.numa.guestMappingPassthrough"
+	numaPassthroughWithoutDedicatedCPUsErr      = "guestMappingPassthrough NUMA provided by the instance type requires " +
+		"dedicatedCPUPlacement to be enabled by the instance type or VMI"
+)
+
+// validateNUMA ensures guest NUMA mapping passthrough requested by the instancetype is accompanied by dedicated CPUs
+func validateNUMA(instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec, vmiSpec *virtv1.VirtualMachineInstanceSpec) *conflict.Conflict {
+	if instancetypeSpec.CPU.NUMA == nil || instancetypeSpec.CPU.NUMA.GuestMappingPassthrough == nil || vmiSpec.Domain.CPU.NUMA != nil {
+		return nil
+	}
+	if vmiSpec.Domain.CPU.DedicatedCPUPlacement ||
+		(instancetypeSpec.CPU.DedicatedCPUPlacement != nil && *instancetypeSpec.CPU.DedicatedCPUPlacement) {
+		return nil
+	}
+	return conflict.NewWithMessage(numaPassthroughWithoutDedicatedCPUsErr, instancetypeNUMAGuestMappingPassthroughPath)
+}
+
+const (
+	instancetypeIsolateEmulatorThreadPath        = "instancetype.spec.cpu.isolateEmulatorThread"
+	isolateEmulatorThreadWithoutDedicatedCPUsErr = "isolateEmulatorThread provided by the instance type requires dedicatedCPUPlacement " +
+		"to be enabled by the instance type or VMI"
+)
+
+// validateIsolateEmulatorThread ensures an isolated emulator thread requested by the instancetype is accompanied by dedicated CPUs
+func validateIsolateEmulatorThread(
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+) *conflict.Conflict {
+	if instancetypeSpec.CPU.IsolateEmulatorThread == nil || !*instancetypeSpec.CPU.IsolateEmulatorThread ||
+		vmiSpec.Domain.CPU.IsolateEmulatorThread {
+		return nil
+	}
+	if vmiSpec.Domain.CPU.DedicatedCPUPlacement ||
+		(instancetypeSpec.CPU.DedicatedCPUPlacement != nil && *instancetypeSpec.CPU.DedicatedCPUPlacement) {
+		return nil
+	}
+	return conflict.NewWithMessage(isolateEmulatorThreadWithoutDedicatedCPUsErr, instancetypeIsolateEmulatorThreadPath)
+}
+
+const (
+	instancetypeMaxSocketsPath      = "instancetype.spec.cpu.maxSockets"
+	maxSocketsLessThanSocketsErrFmt = "maxSockets %d provided by the instance type must be greater than or equal to " +
+		"the %d sockets applied to the VMI"
+)
+
+// validateMaxSockets ensures any maxSockets provided by the instancetype allows for the sockets of the VMI, either those
+// already provided by the VMI or those of the preferred topology that capSocketsAtMaxSockets can not move onto cores
+func validateMaxSockets(
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+) *conflict.Conflict {
+	if instancetypeSpec.CPU.MaxSockets == nil || vmiSpec.Domain.CPU.MaxSockets != *instancetypeSpec.CPU.MaxSockets {
+		return nil
+	}
+	maxSockets := vmiSpec.Domain.CPU.MaxSockets
+	sockets := vmiSpec.Domain.CPU.Sockets
+	if sockets == 0 && vmiSpec.Domain.CPU.Cores == 0 && vmiSpec.Domain.CPU.Threads == 0 {
+		if maxSockets != 0 {
+			return nil
+		}
+		var topology virtv1.CPU
+		layoutGuestCPUTopology(instancetypeSpec.CPU.Guest, preferenceSpec, &topology)
+		sockets = topology.Sockets
+	}
+	if maxSockets >= sockets {
+		return nil
+	}
+	return conflict.NewWithMessage(
+		fmt.Sprintf(maxSocketsLessThanSocketsErrFmt, maxSockets, sockets),
+		instancetypeMaxSocketsPath,
+	)
+}
+
+// capSocketsAtMaxSockets ensures the sockets of the applied topology leave room for CPU hotplug up to maxSockets by
+// moving any sockets above the ceiling onto cores, using the most sockets that still divide the cores of the topology.
+// Threads are left untouched. A maxSockets of 0 can not hold any sockets and is rejected by validateMaxSockets beforehand.
+func capSocketsAtMaxSockets(maxSockets uint32, cpu *virtv1.CPU) {
+	if maxSockets == 0 || cpu.Sockets <= maxSockets {
+		return
+	}
+	totalCores := cpu.Sockets * cpu.Cores
+	for sockets := maxSockets; sockets > 0; sockets-- {
+		if totalCores%sockets == 0 {
+			cpu.Sockets = sockets
+			cpu.Cores = totalCores / sockets
+			return
+		}
+	}
+}
+
+// applyGuestCPUTopology lays out the vCPUs of the instancetype using the preferred topology, capping the sockets at
+// any maxSockets provided by the instancetype
+func applyGuestCPUTopology(
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
+	cpu *virtv1.CPU,
+) {
+	layoutGuestCPUTopology(instancetypeSpec.CPU.Guest, preferenceSpec, cpu)
+	if instancetypeSpec.CPU.MaxSockets != nil {
+		capSocketsAtMaxSockets(*instancetypeSpec.CPU.MaxSockets, cpu)
+	}
+}
+
+// hasGuestCPUTopology reports whether the topology of the VMI is exactly the one applyGuestCPUTopology would apply
+func hasGuestCPUTopology(
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
+	vmiCPU *virtv1.CPU,
+) bool {
+	// A preferred spread that can not lay out the vCPUs is reported once the topology is applied
+	if instancetypeSpec.CPU.Guest > 1 && validation.CheckSpreadCPUTopology(instancetypeSpec, preferenceSpec) != nil {
+		return false
+	}
+	var topology virtv1.CPU
+	applyGuestCPUTopology(instancetypeSpec, preferenceSpec, &topology)
+	return vmiCPU.Sockets == topology.Sockets && vmiCPU.Cores == topology.Cores && vmiCPU.Threads == topology.Threads
+}
+
+func layoutGuestCPUTopology(vCPUs uint32, preferenceSpec *v1beta1.VirtualMachinePreferenceSpec, cpu *virtv1.CPU) {
 	// Apply the default topology here to avoid duplication below
-	vmiSpec.Domain.CPU.Cores = 1
-	vmiSpec.Domain.CPU.Sockets = 1
-	vmiSpec.Domain.CPU.Threads = 1
+	cpu.Cores = 1
+	cpu.Sockets = 1
+	cpu.Threads = 1
 
 	if vCPUs == 1 {
 		return
@@ -85,32 +239,36 @@ func applyGuestCPUTopology(vCPUs uint32, preferenceSpec *v1beta1.VirtualMachineP
 
 	switch preferenceApply.GetPreferredTopology(preferenceSpec) {
 	case v1beta1.DeprecatedPreferCores, v1beta1.Cores:
-		vmiSpec.Domain.CPU.Cores = vCPUs
+		cpu.Cores = vCPUs
 	case v1beta1.DeprecatedPreferSockets, v1beta1.DeprecatedPreferAny, v1beta1.Sockets, v1beta1.Any:
-		vmiSpec.Domain.CPU.Sockets = vCPUs
+		cpu.Sockets = vCPUs
 	case v1beta1.DeprecatedPreferThreads, v1beta1.Threads:
-		vmiSpec.Domain.CPU.Threads = vCPUs
+		cpu.Threads = vCPUs
 	case v1beta1.DeprecatedPreferSpread, v1beta1.Spread:
 		ratio, across := preferenceApply.GetSpreadOptions(preferenceSpec)
 		switch across {
 		case v1beta1.SpreadAcrossSocketsCores:
-			vmiSpec.Domain.CPU.Cores = ratio
-			vmiSpec.Domain.CPU.Sockets = vCPUs / ratio
+			cpu.Cores = ratio
+			cpu.Sockets = vCPUs / ratio
 		case v1beta1.SpreadAcrossCoresThreads:
-			vmiSpec.Domain.CPU.Threads = ratio
-			vmiSpec.Domain.CPU.Cores = vCPUs / ratio
+			cpu.Threads = ratio
+			cpu.Cores = vCPUs / ratio
 		case v1beta1.SpreadAcrossSocketsCoresThreads:
 			const threadsPerCore = 2
-			vmiSpec.Domain.CPU.Threads = threadsPerCore
-			vmiSpec.Domain.CPU.Cores = ratio
-			vmiSpec.Domain.CPU.Sockets = vCPUs / threadsPerCore / ratio
+			cpu.Threads = threadsPerCore
+			cpu.Cores = ratio
+			cpu.Sockets = vCPUs / threadsPerCore / ratio
 		}
 	}
 }
 
+const dedicatedCPUPlacementCollidesErrFmt = "dedicatedCPUPlacement requested by the VMI collides with the %d vCPUs " +
+	"provided by the instance type with dedicatedCPUPlacement %t"
+
 func validateCPU(
 	baseConflict *conflict.Conflict,
 	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
 	vmiSpec *virtv1.VirtualMachineInstanceSpec,
 ) (conflicts conflict.Conflicts) {
 	if _, hasCPURequests := vmiSpec.Domain.Resources.Requests[k8sv1.ResourceCPU]; hasCPURequests {
@@ -121,36 +279,66 @@ func validateCPU(
 		conflicts = append(conflicts, baseConflict.NewChild("domain", "resources", "limits", string(k8sv1.ResourceCPU)))
 	}
 
-	if vmiSpec.Domain.CPU.Sockets != 0 {
-		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "sockets"))
+	conflicts = append(conflicts, validateCPUTopology(baseConflict, instancetypeSpec, preferenceSpec, vmiSpec)...)
+
+	if vmiSpec.Domain.CPU.Model != "" && instancetypeSpec.CPU.Model != nil && vmiSpec.Domain.CPU.Model != *instancetypeSpec.CPU.Model {
+		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "model"))
 	}
 
-	if vmiSpec.Domain.CPU.Cores != 0 {
-		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "cores"))
+	if vmiSpec.Domain.CPU.DedicatedCPUPlacement && instancetypeSpec.CPU.DedicatedCPUPlacement != nil &&
+		!*instancetypeSpec.CPU.DedicatedCPUPlacement {
+		dedicatedConflict := baseConflict.NewChild("domain", "cpu", "dedicatedCPUPlacement")
+		dedicatedConflict.Message = fmt.Sprintf(dedicatedCPUPlacementCollidesErrFmt,
+			instancetypeSpec.CPU.Guest, *instancetypeSpec.CPU.DedicatedCPUPlacement)
+		conflicts = append(conflicts, dedicatedConflict)
 	}
 
-	if vmiSpec.Domain.CPU.Threads != 0 {
-		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "threads"))
+	if vmiSpec.Domain.CPU.IsolateEmulatorThread && instancetypeSpec.CPU.IsolateEmulatorThread != nil &&
+		!*instancetypeSpec.CPU.IsolateEmulatorThread {
+		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "isolateEmulatorThread"))
 	}
 
-	if vmiSpec.Domain.CPU.Model != "" && instancetypeSpec.CPU.Model != nil {
-		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "model"))
+	if vmiSpec.Domain.CPU.NUMA != nil && instancetypeSpec.CPU.NUMA != nil &&
+		!equality.Semantic.DeepEqual(vmiSpec.Domain.CPU.NUMA, instancetypeSpec.CPU.NUMA) {
+		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "numa"))
 	}
 
-	if vmiSpec.Domain.CPU.DedicatedCPUPlacement && instancetypeSpec.CPU.DedicatedCPUPlacement != nil {
-		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "dedicatedCPUPlacement"))
+	if vmiSpec.Domain.CPU.Realtime != nil && instancetypeSpec.CPU.Realtime != nil &&
+		!equality.Semantic.DeepEqual(vmiSpec.Domain.CPU.Realtime, instancetypeSpec.CPU.Realtime) {
+		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "realtime"))
 	}
 
-	if vmiSpec.Domain.CPU.IsolateEmulatorThread && instancetypeSpec.CPU.IsolateEmulatorThread != nil {
-		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "isolateEmulatorThread"))
+	if vmiSpec.Domain.CPU.MaxSockets != 0 && instancetypeSpec.CPU.MaxSockets != nil &&
+		vmiSpec.Domain.CPU.MaxSockets != *instancetypeSpec.CPU.MaxSockets {
+		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "maxSockets"))
 	}
 
-	if vmiSpec.Domain.CPU.NUMA != nil && instancetypeSpec.CPU.NUMA != nil {
-		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "numa"))
+	return conflicts
+}
+
+// validateCPUTopology returns a conflict for each of sockets, cores and threads provided by the VMI unless the VMI
+// already has exactly the topology the instancetype and preference would apply
+func validateCPUTopology(
+	baseConflict *conflict.Conflict,
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+) (conflicts conflict.Conflicts) {
+	vmiCPU := vmiSpec.Domain.CPU
+	if (vmiCPU.Sockets == 0 && vmiCPU.Cores == 0 && vmiCPU.Threads == 0) || hasGuestCPUTopology(instancetypeSpec, preferenceSpec, vmiCPU) {
+		return nil
 	}
 
-	if vmiSpec.Domain.CPU.Realtime != nil && instancetypeSpec.CPU.Realtime != nil {
-		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "realtime"))
+	if vmiCPU.Sockets != 0 {
+		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "sockets"))
+	}
+
+	if vmiCPU.Cores != 0 {
+		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "cores"))
+	}
+
+	if vmiCPU.Threads != 0 {
+		conflicts = append(conflicts, baseConflict.NewChild("domain", "cpu", "threads"))
 	}
 
 	return conflicts
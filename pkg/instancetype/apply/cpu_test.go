@@ -374,8 +374,8 @@ var _ = Describe("instancetype.spec.CPU and preference.spec.CPU", func() {
 				},
 				virtv1.CPU{Sockets: 3, Cores: 4, Threads: 2},
 			),
-			Entry("to SocketsCoresThreads with 36 vCPUs and a ratio of 1:4:2",
-				uint32(36),
+			Entry("to SocketsCoresThreads with 32 vCPUs and a ratio of 1:4:2",
+				uint32(32),
 				v1beta1.VirtualMachinePreferenceSpec{
 					CPU: &v1beta1.CPUPreferences{
 						SpreadOptions: &v1beta1.SpreadOptions{
@@ -431,8 +431,130 @@ var _ = Describe("instancetype.spec.CPU and preference.spec.CPU", func() {
 				virtv1.CPU{Sockets: 1, Cores: 4, Threads: 2},
 			),
 		)
+
+		DescribeTable("should spread vCPUs exactly using the provided ratio", func(vCPUs, ratio uint32, across v1beta1.SpreadAcross) {
+			instancetypeSpec.CPU.Guest = vCPUs
+			preferenceSpec.CPU.PreferredCPUTopology = pointer.P(v1beta1.Spread)
+			preferenceSpec.CPU.SpreadOptions = &v1beta1.SpreadOptions{
+				Across: pointer.P(across),
+				Ratio:  pointer.P(ratio),
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.Sockets * vmi.Spec.Domain.CPU.Cores * vmi.Spec.Domain.CPU.Threads).To(Equal(vCPUs))
+		},
+			Entry("with 10 vCPUs across SocketsCores and a ratio of 5", uint32(10), uint32(5), v1beta1.SpreadAcrossSocketsCores),
+			Entry("with 20 vCPUs across SocketsCores and a ratio of 4", uint32(20), uint32(4), v1beta1.SpreadAcrossSocketsCores),
+			Entry("with 30 vCPUs across SocketsCoresThreads and a ratio of 5", uint32(30), uint32(5), v1beta1.SpreadAcrossSocketsCoresThreads),
+			Entry("with 10 vCPUs across CoresThreads and a ratio of 2", uint32(10), uint32(2), v1beta1.SpreadAcrossCoresThreads),
+		)
+
+		DescribeTable("should return a conflict when vCPUs can not be spread using the provided ratio",
+			func(vCPUs, ratio uint32, across v1beta1.SpreadAcross, expectedMessage string) {
+				instancetypeSpec.CPU.Guest = vCPUs
+				preferenceSpec.CPU.PreferredCPUTopology = pointer.P(v1beta1.Spread)
+				preferenceSpec.CPU.SpreadOptions = &v1beta1.SpreadOptions{
+					Across: pointer.P(across),
+					Ratio:  pointer.P(ratio),
+				}
+
+				conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+				Expect(conflicts).To(HaveLen(1))
+				Expect(conflicts[0].String()).To(Equal("instancetype.spec.cpu.guest"))
+				Expect(conflicts[0].Error()).To(Equal(expectedMessage))
+				Expect(vmi.Spec.Domain.CPU.Sockets).To(BeZero())
+				Expect(vmi.Spec.Domain.CPU.Cores).To(BeZero())
+				Expect(vmi.Spec.Domain.CPU.Threads).To(BeZero())
+			},
+			Entry("with 3 vCPUs across SocketsCores and a ratio of 2", uint32(3), uint32(2), v1beta1.SpreadAcrossSocketsCores,
+				"3 vCPUs provided by the instance type are not divisible by the Spec.PreferSpreadSocketToCoreRatio "+
+					"or Spec.CPU.PreferSpreadOptions.Ratio of 2 provided by the preference"),
+			Entry("with 6 vCPUs across SocketsCores and a ratio of 4", uint32(6), uint32(4), v1beta1.SpreadAcrossSocketsCores,
+				"6 vCPUs provided by the instance type are not divisible by the Spec.PreferSpreadSocketToCoreRatio "+
+					"or Spec.CPU.PreferSpreadOptions.Ratio of 4 provided by the preference"),
+			Entry("with 6 vCPUs across SocketsCoresThreads and a ratio of 2", uint32(6), uint32(2), v1beta1.SpreadAcrossSocketsCoresThreads,
+				"6 vCPUs provided by the instance type are not divisible by the number of threads per core 2 "+
+					"and Spec.PreferSpreadSocketToCoreRatio or Spec.CPU.PreferSpreadOptions.Ratio of 2"),
+			Entry("with 5 vCPUs across CoresThreads and a ratio of 2", uint32(5), uint32(2), v1beta1.SpreadAcrossCoresThreads,
+				"5 vCPUs provided by the instance type are not divisible by the number of threads per core 2"),
+			Entry("with 2 vCPUs across SocketsCores and a ratio greater than the vCPUs", uint32(2), uint32(4), v1beta1.SpreadAcrossSocketsCores,
+				"2 vCPUs provided by the instance type are not divisible by the Spec.PreferSpreadSocketToCoreRatio "+
+					"or Spec.CPU.PreferSpreadOptions.Ratio of 4 provided by the preference"),
+		)
+
+		It("should not return a conflict when a single vCPU can not be spread using the provided ratio", func() {
+			instancetypeSpec.CPU.Guest = 1
+			preferenceSpec.CPU.PreferredCPUTopology = pointer.P(v1beta1.Spread)
+			preferenceSpec.CPU.SpreadOptions = &v1beta1.SpreadOptions{
+				Ratio: pointer.P(uint32(4)),
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(uint32(1)))
+			Expect(vmi.Spec.Domain.CPU.Cores).To(Equal(uint32(1)))
+			Expect(vmi.Spec.Domain.CPU.Threads).To(Equal(uint32(1)))
+		})
+
+		It("should return a conflict instead of spreading vCPUs with a ratio of 0", func() {
+			preferenceSpec.CPU.PreferredCPUTopology = pointer.P(v1beta1.Spread)
+			preferenceSpec.CPU.SpreadOptions = &v1beta1.SpreadOptions{
+				Ratio: pointer.P(uint32(0)),
+			}
+
+			conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].String()).To(Equal("instancetype.spec.cpu.guest"))
+			Expect(conflicts[0].Error()).To(Equal(
+				"a Spec.CPU.PreferSpreadOptions.Ratio of 0 provided by the preference can not be used to spread vCPUs"))
+		})
+
+		DescribeTable("should spread vCPUs across sockets and cores using PreferSpreadSocketToCoreRatio",
+			func(vCPUs, ratio uint32, expectedCPU virtv1.CPU) {
+				instancetypeSpec.CPU.Guest = vCPUs
+				preferenceSpec.PreferSpreadSocketToCoreRatio = ratio
+				preferenceSpec.CPU.PreferredCPUTopology = pointer.P(v1beta1.Spread)
+
+				Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+				Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(expectedCPU.Sockets))
+				Expect(vmi.Spec.Domain.CPU.Cores).To(Equal(expectedCPU.Cores))
+				Expect(vmi.Spec.Domain.CPU.Threads).To(Equal(expectedCPU.Threads))
+			},
+			Entry("with 8 vCPUs and the default ratio", uint32(8), uint32(0), virtv1.CPU{Sockets: 4, Cores: 2, Threads: 1}),
+			Entry("with 8 vCPUs and a ratio of 4", uint32(8), uint32(4), virtv1.CPU{Sockets: 2, Cores: 4, Threads: 1}),
+			Entry("with 12 vCPUs and a ratio of 3", uint32(12), uint32(3), virtv1.CPU{Sockets: 4, Cores: 3, Threads: 1}),
+			Entry("with 16 vCPUs and a ratio of 8", uint32(16), uint32(8), virtv1.CPU{Sockets: 2, Cores: 8, Threads: 1}),
+		)
+
+		It("should return a conflict when vCPUs can not be spread using PreferSpreadSocketToCoreRatio", func() {
+			instancetypeSpec.CPU.Guest = uint32(10)
+			preferenceSpec.PreferSpreadSocketToCoreRatio = uint32(4)
+			preferenceSpec.CPU.PreferredCPUTopology = pointer.P(v1beta1.Spread)
+
+			conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].Error()).To(Equal("10 vCPUs provided by the instance type are not divisible by the " +
+				"Spec.PreferSpreadSocketToCoreRatio or Spec.CPU.PreferSpreadOptions.Ratio of 4 provided by the preference"))
+		})
 	})
 
+	DescribeTable("should apply the vCPUs of the instance type in the topology of the preference",
+		func(topology v1beta1.PreferredCPUTopology, expectedCPU virtv1.CPU) {
+			instancetypeSpec.CPU.Guest = 12
+			instancetypeSpec.CPU.MaxSockets = nil
+			preferenceSpec.CPU.PreferredCPUTopology = pointer.P(topology)
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(expectedCPU.Sockets))
+			Expect(vmi.Spec.Domain.CPU.Cores).To(Equal(expectedCPU.Cores))
+			Expect(vmi.Spec.Domain.CPU.Threads).To(Equal(expectedCPU.Threads))
+		},
+		Entry("of sockets", v1beta1.Sockets, virtv1.CPU{Sockets: 12, Cores: 1, Threads: 1}),
+		Entry("of cores", v1beta1.Cores, virtv1.CPU{Sockets: 1, Cores: 12, Threads: 1}),
+		Entry("of threads", v1beta1.Threads, virtv1.CPU{Sockets: 1, Cores: 1, Threads: 12}),
+		Entry("of any", v1beta1.Any, virtv1.CPU{Sockets: 12, Cores: 1, Threads: 1}),
+		Entry("of spread", v1beta1.Spread, virtv1.CPU{Sockets: 6, Cores: 2, Threads: 1}),
+	)
+
 	It("should return a conflict if vmi.Spec.Domain.CPU already defined", func() {
 		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
 			CPU: v1beta1.CPUInstancetype{
@@ -449,10 +571,378 @@ var _ = Describe("instancetype.spec.CPU and preference.spec.CPU", func() {
 		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
 		Expect(conflicts).To(HaveLen(3))
 		Expect(conflicts).To(Equal(conflict.Conflicts{
-			conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
 			conflict.New("spec", "template", "spec", "domain", "cpu", "cores"),
+			conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
 			conflict.New("spec", "template", "spec", "domain", "cpu", "threads"),
-		}))
+		}.WithSource(conflict.SourceInstancetype)))
+	})
+
+	Context("with CPU sub-fields provided by the VMI", func() {
+		It("should merge a model only VMI with a counts only instance type", func() {
+			instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+				CPU: v1beta1.CPUInstancetype{
+					Guest: uint32(2),
+				},
+			}
+			vmi.Spec.Domain.CPU = &virtv1.CPU{
+				Model: "Haswell",
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.Model).To(Equal("Haswell"))
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(instancetypeSpec.CPU.Guest))
+			Expect(vmi.Spec.Domain.CPU.Cores).To(Equal(uint32(1)))
+			Expect(vmi.Spec.Domain.CPU.Threads).To(Equal(uint32(1)))
+		})
+
+		It("should merge sub-fields not provided by the instance type", func() {
+			instancetypeSpec.CPU.Model = nil
+			vmi.Spec.Domain.CPU = &virtv1.CPU{
+				Model: "Haswell",
+				Features: []virtv1.CPUFeature{{
+					Name:   "vmx",
+					Policy: "require",
+				}},
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.Model).To(Equal("Haswell"))
+			Expect(vmi.Spec.Domain.CPU.Features).To(Equal([]virtv1.CPUFeature{{Name: "vmx", Policy: "require"}}))
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(instancetypeSpec.CPU.Guest))
+			Expect(vmi.Spec.Domain.CPU.DedicatedCPUPlacement).To(BeTrue())
+			Expect(vmi.Spec.Domain.CPU.MaxSockets).To(Equal(*instancetypeSpec.CPU.MaxSockets))
+		})
+
+		DescribeTable("should only return a conflict for the sub-field provided by both", func(vmiCPU *virtv1.CPU, expectedPath string) {
+			vmi.Spec.Domain.CPU = vmiCPU
+
+			conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].String()).To(Equal(expectedPath))
+		},
+			Entry("of sockets", &virtv1.CPU{Sockets: 2}, "spec.template.spec.domain.cpu.sockets"),
+			Entry("of model", &virtv1.CPU{Model: "Haswell"}, "spec.template.spec.domain.cpu.model"),
+			Entry("of numa", &virtv1.CPU{NUMA: &virtv1.NUMA{}}, "spec.template.spec.domain.cpu.numa"),
+			Entry("of realtime", &virtv1.CPU{Realtime: &virtv1.Realtime{}}, "spec.template.spec.domain.cpu.realtime"),
+			Entry("of maxSockets", &virtv1.CPU{MaxSockets: 8}, "spec.template.spec.domain.cpu.maxSockets"),
+		)
+
+		It("should return a conflict for isolateEmulatorThread requested by the VMI and disabled by the instance type", func() {
+			instancetypeSpec.CPU.IsolateEmulatorThread = pointer.P(false)
+			vmi.Spec.Domain.CPU = &virtv1.CPU{
+				IsolateEmulatorThread: true,
+			}
+
+			conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(Equal(conflict.Conflicts{
+				conflict.New("spec", "template", "spec", "domain", "cpu", "isolateEmulatorThread"),
+			}.WithSource(conflict.SourceInstancetype)))
+		})
+
+		DescribeTable("should accept a sub-field identical to that of the instance type", func(vmiCPU *virtv1.CPU) {
+			vmi.Spec.Domain.CPU = vmiCPU
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(instancetypeSpec.CPU.Guest))
+			Expect(vmi.Spec.Domain.CPU.Cores).To(Equal(uint32(1)))
+			Expect(vmi.Spec.Domain.CPU.Threads).To(Equal(uint32(1)))
+		},
+			Entry("of the topology", &virtv1.CPU{Sockets: 2, Cores: 1, Threads: 1}),
+			Entry("of model", &virtv1.CPU{Model: "host-passthrough"}),
+			Entry("of dedicatedCPUPlacement", &virtv1.CPU{DedicatedCPUPlacement: true}),
+			Entry("of isolateEmulatorThread", &virtv1.CPU{IsolateEmulatorThread: true}),
+			Entry("of numa", &virtv1.CPU{NUMA: &virtv1.NUMA{GuestMappingPassthrough: &virtv1.NUMAGuestMappingPassthrough{}}}),
+			Entry("of realtime", &virtv1.CPU{Realtime: &virtv1.Realtime{Mask: "0-3,^1"}}),
+			Entry("of maxSockets", &virtv1.CPU{MaxSockets: 6}),
+		)
+
+		It("should return conflicts for a topology differing from that of the preference", func() {
+			preferenceSpec.CPU.PreferredCPUTopology = pointer.P(v1beta1.Cores)
+			vmi.Spec.Domain.CPU = &virtv1.CPU{Sockets: 2, Cores: 1, Threads: 1}
+
+			conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(Equal(conflict.Conflicts{
+				conflict.New("spec", "template", "spec", "domain", "cpu", "cores"),
+				conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
+				conflict.New("spec", "template", "spec", "domain", "cpu", "threads"),
+			}.WithSource(conflict.SourceInstancetype)))
+		})
+	})
+
+	Context("with dedicatedCPUPlacement", func() {
+		BeforeEach(func() {
+			instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+				CPU: v1beta1.CPUInstancetype{
+					Guest: uint32(2),
+				},
+			}
+		})
+
+		It("should return a conflict when requested by the VMI and disabled by the instance type", func() {
+			instancetypeSpec.CPU.DedicatedCPUPlacement = pointer.P(false)
+			vmi.Spec.Domain.CPU = &virtv1.CPU{
+				DedicatedCPUPlacement: true,
+			}
+
+			conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.cpu.dedicatedCPUPlacement"))
+			Expect(conflicts[0].Error()).To(Equal(
+				"dedicatedCPUPlacement requested by the VMI collides with the 2 vCPUs provided by the instance type with dedicatedCPUPlacement false"))
+		})
+
+		It("should accept when requested by the VMI and enabled by the instance type", func() {
+			instancetypeSpec.CPU.DedicatedCPUPlacement = pointer.P(true)
+			vmi.Spec.Domain.CPU = &virtv1.CPU{
+				DedicatedCPUPlacement: true,
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.DedicatedCPUPlacement).To(BeTrue())
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(instancetypeSpec.CPU.Guest))
+		})
+
+		It("should apply the vCPUs of the instance type when only requested by the VMI", func() {
+			vmi.Spec.Domain.CPU = &virtv1.CPU{
+				DedicatedCPUPlacement: true,
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.DedicatedCPUPlacement).To(BeTrue())
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(instancetypeSpec.CPU.Guest))
+		})
+
+		It("should apply when only provided by the instance type", func() {
+			instancetypeSpec.CPU.DedicatedCPUPlacement = pointer.P(true)
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.DedicatedCPUPlacement).To(BeTrue())
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(instancetypeSpec.CPU.Guest))
+		})
+
+		It("should only warn when VMI overrides are enabled", func() {
+			instancetypeSpec.CPU.DedicatedCPUPlacement = pointer.P(false)
+			vmi.Spec.Domain.CPU = &virtv1.CPU{
+				DedicatedCPUPlacement: true,
+			}
+
+			var warnings conflict.Conflicts
+			overridingApplier := apply.NewVMIApplier(
+				apply.WithVMIOverrides(),
+				apply.WithWarningHandler(func(warning *conflict.Conflict) {
+					warnings = append(warnings, warning)
+				}),
+			)
+			Expect(overridingApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0].Error()).To(HavePrefix("dedicatedCPUPlacement requested by the VMI collides"))
+			Expect(vmi.Spec.Domain.CPU.DedicatedCPUPlacement).To(BeTrue())
+		})
+	})
+
+	Context("with isolateEmulatorThread", func() {
+		BeforeEach(func() {
+			instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+				CPU: v1beta1.CPUInstancetype{
+					Guest:                 uint32(2),
+					IsolateEmulatorThread: pointer.P(true),
+				},
+			}
+		})
+
+		It("should apply when the instancetype provides dedicated CPUs", func() {
+			instancetypeSpec.CPU.DedicatedCPUPlacement = pointer.P(true)
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.IsolateEmulatorThread).To(BeTrue())
+			Expect(vmi.Spec.Domain.CPU.DedicatedCPUPlacement).To(BeTrue())
+		})
+
+		It("should apply when the VMI provides dedicated CPUs", func() {
+			vmi.Spec.Domain.CPU = &virtv1.CPU{
+				DedicatedCPUPlacement: true,
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.IsolateEmulatorThread).To(BeTrue())
+		})
+
+		It("should not apply or return a conflict when disabled by the instancetype", func() {
+			instancetypeSpec.CPU.IsolateEmulatorThread = pointer.P(false)
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.IsolateEmulatorThread).To(BeFalse())
+		})
+
+		DescribeTable("should return a conflict without dedicated CPUs", func(dedicatedCPUPlacement *bool) {
+			instancetypeSpec.CPU.DedicatedCPUPlacement = dedicatedCPUPlacement
+
+			conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].String()).To(Equal("instancetype.spec.cpu.isolateEmulatorThread"))
+			Expect(conflicts[0].Error()).To(Equal("isolateEmulatorThread provided by the instance type " +
+				"requires dedicatedCPUPlacement to be enabled by the instance type or VMI"))
+			Expect(conflicts[0].Source).To(Equal(conflict.SourceInstancetype))
+			Expect(vmi.Spec.Domain.CPU.IsolateEmulatorThread).To(BeFalse())
+		},
+			Entry("when dedicatedCPUPlacement is not provided", nil),
+			Entry("when dedicatedCPUPlacement is disabled", pointer.P(false)),
+		)
+	})
+
+	Context("with NUMA guestMappingPassthrough", func() {
+		BeforeEach(func() {
+			instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+				CPU: v1beta1.CPUInstancetype{
+					Guest: uint32(2),
+					NUMA: &virtv1.NUMA{
+						GuestMappingPassthrough: &virtv1.NUMAGuestMappingPassthrough{},
+					},
+				},
+			}
+		})
+
+		It("should apply when the instancetype provides dedicated CPUs", func() {
+			instancetypeSpec.CPU.DedicatedCPUPlacement = pointer.P(true)
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.NUMA).To(HaveValue(Equal(*instancetypeSpec.CPU.NUMA)))
+			Expect(vmi.Spec.Domain.CPU.DedicatedCPUPlacement).To(BeTrue())
+		})
+
+		It("should apply when the VMI provides dedicated CPUs", func() {
+			vmi.Spec.Domain.CPU = &virtv1.CPU{
+				DedicatedCPUPlacement: true,
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.NUMA).To(HaveValue(Equal(*instancetypeSpec.CPU.NUMA)))
+		})
+
+		DescribeTable("should return a conflict without dedicated CPUs", func(dedicatedCPUPlacement *bool) {
+			instancetypeSpec.CPU.DedicatedCPUPlacement = dedicatedCPUPlacement
+
+			conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].String()).To(Equal("instancetype.spec.cpu.numa.guestMappingPassthrough"))
+			Expect(conflicts[0].Error()).To(Equal("guestMappingPassthrough NUMA provided by the instance type " +
+				"requires dedicatedCPUPlacement to be enabled by the instance type or VMI"))
+			Expect(vmi.Spec.Domain.CPU.NUMA).To(BeNil())
+		},
+			Entry("when dedicatedCPUPlacement is not provided", nil),
+			Entry("when dedicatedCPUPlacement is disabled", pointer.P(false)),
+		)
+	})
+
+	It("should return a conflict if vmi.Spec.Domain.CPU.MaxSockets already defined", func() {
+		vmi.Spec.Domain.CPU = &virtv1.CPU{
+			MaxSockets: 8,
+		}
+
+		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(Equal(conflict.Conflicts{
+			conflict.New("spec", "template", "spec", "domain", "cpu", "maxSockets"),
+		}.WithSource(conflict.SourceInstancetype)))
+	})
+
+	It("should apply maxSockets equal to the applied sockets", func() {
+		instancetypeSpec.CPU.MaxSockets = pointer.P(instancetypeSpec.CPU.Guest)
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(instancetypeSpec.CPU.Guest))
+		Expect(vmi.Spec.Domain.CPU.MaxSockets).To(Equal(instancetypeSpec.CPU.Guest))
+	})
+
+	It("should move sockets above maxSockets onto cores", func() {
+		instancetypeSpec.CPU.Guest = uint32(4)
+		instancetypeSpec.CPU.MaxSockets = pointer.P(uint32(2))
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(uint32(2)))
+		Expect(vmi.Spec.Domain.CPU.Cores).To(Equal(uint32(2)))
+		Expect(vmi.Spec.Domain.CPU.Threads).To(Equal(uint32(1)))
+		Expect(vmi.Spec.Domain.CPU.MaxSockets).To(Equal(uint32(2)))
+	})
+
+	DescribeTable("should cap the sockets of a spread topology at maxSockets",
+		func(vCPUs, maxSockets uint32, spreadOptions *v1beta1.SpreadOptions, expectedCPU virtv1.CPU) {
+			instancetypeSpec.CPU.Guest = vCPUs
+			instancetypeSpec.CPU.MaxSockets = pointer.P(maxSockets)
+			preferenceSpec.CPU.PreferredCPUTopology = pointer.P(v1beta1.Spread)
+			preferenceSpec.CPU.SpreadOptions = spreadOptions
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(expectedCPU.Sockets))
+			Expect(vmi.Spec.Domain.CPU.Cores).To(Equal(expectedCPU.Cores))
+			Expect(vmi.Spec.Domain.CPU.Threads).To(Equal(expectedCPU.Threads))
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(BeNumerically("<=", maxSockets))
+		},
+		Entry("with 8 vCPUs across SocketsCores, a default ratio of 2 and 3 maxSockets",
+			uint32(8), uint32(3), &v1beta1.SpreadOptions{},
+			virtv1.CPU{Sockets: 2, Cores: 4, Threads: 1},
+		),
+		Entry("with 12 vCPUs across SocketsCores, a ratio of 2 and 5 maxSockets",
+			uint32(12), uint32(5), &v1beta1.SpreadOptions{},
+			virtv1.CPU{Sockets: 4, Cores: 3, Threads: 1},
+		),
+		Entry("with 16 vCPUs across SocketsCoresThreads, a ratio of 2 and 2 maxSockets",
+			uint32(16), uint32(2), &v1beta1.SpreadOptions{Across: pointer.P(v1beta1.SpreadAcrossSocketsCoresThreads)},
+			virtv1.CPU{Sockets: 2, Cores: 4, Threads: 2},
+		),
+		Entry("with 14 vCPUs across SocketsCores, a ratio of 2 and 6 maxSockets",
+			uint32(14), uint32(6), &v1beta1.SpreadOptions{},
+			virtv1.CPU{Sockets: 2, Cores: 7, Threads: 1},
+		),
+		Entry("with 8 vCPUs across SocketsCores, a ratio of 2 and 4 maxSockets left unchanged",
+			uint32(8), uint32(4), &v1beta1.SpreadOptions{},
+			virtv1.CPU{Sockets: 4, Cores: 2, Threads: 1},
+		),
+	)
+
+	It("should return a conflict if the applied sockets can not fit within a maxSockets of 0", func() {
+		instancetypeSpec.CPU.Guest = uint32(4)
+		instancetypeSpec.CPU.MaxSockets = pointer.P(uint32(0))
+
+		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].String()).To(Equal("instancetype.spec.cpu.maxSockets"))
+		Expect(conflicts[0].Error()).To(Equal(
+			"maxSockets 0 provided by the instance type must be greater than or equal to the 4 sockets applied to the VMI"))
+	})
+
+	It("should return a conflict if the sockets provided by the VMI exceed maxSockets", func() {
+		instancetypeSpec.CPU.Guest = uint32(8)
+		instancetypeSpec.CPU.MaxSockets = pointer.P(uint32(4))
+		vmi.Spec.Domain.CPU = &virtv1.CPU{
+			Sockets: 8,
+		}
+
+		var warnings conflict.Conflicts
+		overridingApplier := apply.NewVMIApplier(
+			apply.WithVMIOverrides(),
+			apply.WithWarningHandler(func(warning *conflict.Conflict) {
+				warnings = append(warnings, warning)
+			}),
+		)
+		conflicts := overridingApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0].Path.String()).To(Equal("spec.template.spec.domain.cpu.sockets"))
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].Path.String()).To(Equal("instancetype.spec.cpu.maxSockets"))
+		Expect(conflicts[0].Error()).To(Equal(
+			"maxSockets 4 provided by the instance type must be greater than or equal to the 8 sockets applied to the VMI"))
+		Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(uint32(8)))
+	})
+
+	It("should not return a conflict if maxSockets is less than the vCPUs spread across cores", func() {
+		instancetypeSpec.CPU.Guest = uint32(4)
+		instancetypeSpec.CPU.MaxSockets = pointer.P(uint32(2))
+		preferenceSpec.CPU.PreferredCPUTopology = pointer.P(v1beta1.Cores)
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(uint32(1)))
+		Expect(vmi.Spec.Domain.CPU.Cores).To(Equal(uint32(4)))
+		Expect(vmi.Spec.Domain.CPU.MaxSockets).To(Equal(uint32(2)))
 	})
 
 	It("should return a conflict if vmi.Spec.Domain.Resources.Requests[k8sv1.ResourceCPU] already defined", func() {
@@ -527,4 +1017,21 @@ var _ = Describe("instancetype.spec.CPU and preference.spec.CPU", func() {
 			},
 		}))
 	})
+
+	It("should only apply the first of PreferredCPUFeatures sharing a name", func() {
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			CPU: &v1beta1.CPUPreferences{
+				PreferredCPUFeatures: []virtv1.CPUFeature{
+					{Name: "foo", Policy: "require"},
+					{Name: "bar", Policy: "force"},
+					{Name: "foo", Policy: "disable"},
+				},
+			},
+		}
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.CPU.Features).To(Equal([]virtv1.CPUFeature{
+			{Name: "foo", Policy: "require"},
+			{Name: "bar", Policy: "force"},
+		}))
+	})
 })
@@ -0,0 +1,101 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+package apply
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+)
+
+// MutationSource identifies whether a mutation of the VMI was made by the instancetype or preference
+type MutationSource string
+
+const (
+	MutationSourceInstancetype MutationSource = "Instancetype"
+	MutationSourcePreference   MutationSource = "Preference"
+)
+
+// MutationEvent describes a single field of the VMI mutated while applying an instancetype or preference.
+// Name is only known when the instancetype or preference is applied through ApplyToVMIWithResult.
+type MutationEvent struct {
+	AppliedField
+	Source MutationSource
+	Name   string
+}
+
+// EventSink is called with each mutation made to the VMI, allowing callers such as the VM controller
+// to surface them as events on the VM.
+type EventSink interface {
+	RecordMutation(event MutationEvent)
+}
+
+// mutationRecorder snapshots the VMI before a set of mutations so that each applied field can later be recorded
+type mutationRecorder struct {
+	sink             EventSink
+	field            *k8sfield.Path
+	originalSpec     *virtv1.VirtualMachineInstanceSpec
+	originalMetadata *metav1.ObjectMeta
+}
+
+// newMutationRecorder returns nil when no sink is registered, avoiding the cost of copying the VMI
+func newMutationRecorder(
+	sink EventSink,
+	field *k8sfield.Path,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+	vmiMetadata *metav1.ObjectMeta,
+) *mutationRecorder {
+	if sink == nil {
+		return nil
+	}
+	return &mutationRecorder{
+		sink:             sink,
+		field:            field,
+		originalSpec:     vmiSpec.DeepCopy(),
+		originalMetadata: vmiMetadata.DeepCopy(),
+	}
+}
+
+func (r *mutationRecorder) record(
+	source MutationSource,
+	appliedSource *AppliedSource,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+	vmiMetadata *metav1.ObjectMeta,
+) {
+	if r == nil {
+		return
+	}
+	appliedFields, err := diffVMI(r.field, r.originalSpec, r.originalMetadata, vmiSpec, vmiMetadata)
+	if err != nil {
+		// Events are best effort and must never change the outcome of applying the instancetype or preference
+		return
+	}
+	var name string
+	if appliedSource != nil {
+		name = appliedSource.Name
+	}
+	for _, appliedField := range appliedFields {
+		r.sink.RecordMutation(MutationEvent{
+			AppliedField: appliedField,
+			Source:       source,
+			Name:         name,
+		})
+	}
+}
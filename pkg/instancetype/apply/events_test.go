@@ -0,0 +1,138 @@
+package apply_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+	"kubevirt.io/kubevirt/pkg/libvmi"
+)
+
+type fakeEventSink struct {
+	events []apply.MutationEvent
+}
+
+func (s *fakeEventSink) RecordMutation(event apply.MutationEvent) {
+	s.events = append(s.events, event)
+}
+
+var _ = Describe("WithEventSink", func() {
+	var (
+		vmi              *virtv1.VirtualMachineInstance
+		instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec
+		preferenceSpec   *v1beta1.VirtualMachinePreferenceSpec
+		sink             *fakeEventSink
+
+		field = k8sfield.NewPath("spec", "template", "spec")
+	)
+
+	BeforeEach(func() {
+		vmi = libvmi.New()
+		sink = &fakeEventSink{}
+
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			CPU: v1beta1.CPUInstancetype{
+				Guest: uint32(2),
+			},
+			Memory: v1beta1.MemoryInstancetype{
+				Guest: resource.MustParse("512Mi"),
+			},
+			Annotations: map[string]string{
+				"annotation": "value",
+			},
+		}
+
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			Machine: &v1beta1.MachinePreferences{
+				PreferredMachineType: "q35",
+			},
+		}
+	})
+
+	newMutationEvent := func(source apply.MutationSource, path *k8sfield.Path, value interface{}) apply.MutationEvent {
+		return apply.MutationEvent{
+			AppliedField: apply.AppliedField{Field: path, Value: value},
+			Source:       source,
+		}
+	}
+
+	It("should record an event for each mutation made by the instancetype and preference", func() {
+		vmiApplier := apply.NewVMIApplier(apply.WithEventSink(sink))
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+		Expect(sink.events).To(ConsistOf(
+			newMutationEvent(apply.MutationSourceInstancetype, field.Child("domain", "cpu", "sockets"), uint64(2)),
+			newMutationEvent(apply.MutationSourceInstancetype, field.Child("domain", "cpu", "cores"), uint64(1)),
+			newMutationEvent(apply.MutationSourceInstancetype, field.Child("domain", "cpu", "threads"), uint64(1)),
+			newMutationEvent(apply.MutationSourceInstancetype, field.Child("domain", "memory", "guest"), "512Mi"),
+			newMutationEvent(apply.MutationSourceInstancetype, k8sfield.NewPath("annotations", "annotation"), "value"),
+			newMutationEvent(apply.MutationSourcePreference, field.Child("domain", "machine", "type"), "q35"),
+		))
+	})
+
+	It("should name the instancetype and preference when applied with a result", func() {
+		vmiApplier := apply.NewVMIApplier(apply.WithEventSink(sink))
+		result := vmiApplier.ApplyToVMIWithResult(
+			field,
+			&apply.AppliedSource{Kind: "VirtualMachineInstancetype", Name: "instancetype"},
+			instancetypeSpec,
+			&apply.AppliedSource{Kind: "VirtualMachinePreference", Name: "preference"},
+			preferenceSpec,
+			&vmi.Spec,
+			&vmi.ObjectMeta,
+		)
+		Expect(result.Conflicts).To(BeEmpty())
+
+		Expect(sink.events).ToNot(BeEmpty())
+		for _, event := range sink.events {
+			switch event.Source {
+			case apply.MutationSourceInstancetype:
+				Expect(event.Name).To(Equal("instancetype"))
+			case apply.MutationSourcePreference:
+				Expect(event.Name).To(Equal("preference"))
+			}
+		}
+	})
+
+	It("should not record any events when the instancetype conflicts with the VMI", func() {
+		vmi.Spec.Domain.CPU = &virtv1.CPU{
+			Sockets: 4,
+		}
+
+		vmiApplier := apply.NewVMIApplier(apply.WithEventSink(sink))
+		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(Equal(conflict.Conflicts{
+			conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
+		}.WithSource(conflict.SourceInstancetype)))
+		Expect(sink.events).To(BeEmpty())
+	})
+
+	It("should return the same conflicts with and without a sink", func() {
+		vmi.Spec.Domain.CPU = &virtv1.CPU{
+			Sockets: 4,
+		}
+		vmiCopy := vmi.DeepCopy()
+
+		withSink := apply.NewVMIApplier(apply.WithEventSink(sink)).ApplyToVMI(
+			field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		withoutSink := apply.NewVMIApplier().ApplyToVMI(
+			field, instancetypeSpec, preferenceSpec, &vmiCopy.Spec, &vmiCopy.ObjectMeta)
+		Expect(withSink).To(Equal(withoutSink))
+	})
+
+	It("should only record events from the preference when no instancetype is provided", func() {
+		vmiApplier := apply.NewVMIApplier(apply.WithEventSink(sink))
+		Expect(vmiApplier.ApplyToVMI(field, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+		Expect(sink.events).To(ConsistOf(
+			newMutationEvent(apply.MutationSourcePreference, field.Child("domain", "machine", "type"), "q35"),
+		))
+	})
+})
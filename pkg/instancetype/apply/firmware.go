@@ -0,0 +1,51 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+package apply
+
+import (
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+	preferenceApply "kubevirt.io/kubevirt/pkg/instancetype/preference/apply"
+)
+
+const secureBootRequiresSMMErr = "EFI SecureBoot provided by the preference requires SMM, which is disabled"
+
+func hasEFIBootloader(vmiSpec *virtv1.VirtualMachineInstanceSpec) bool {
+	firmware := vmiSpec.Domain.Firmware
+	return firmware != nil && firmware.Bootloader != nil && firmware.Bootloader.EFI != nil
+}
+
+// checkPreferredSecureBootSMM returns a conflict when EFI SecureBoot applied from the preference
+// can not be used as SMM has been explicitly disabled by the VMI or preference.
+func checkPreferredSecureBootSMM(field *k8sfield.Path, efiProvidedByVMI bool, vmiSpec *virtv1.VirtualMachineInstanceSpec) *conflict.Conflict {
+	if efiProvidedByVMI || !preferenceApply.IsSecureBootEnabled(vmiSpec) {
+		return nil
+	}
+	if features := vmiSpec.Domain.Features; features != nil && features.SMM != nil &&
+		(features.SMM.Enabled == nil || *features.SMM.Enabled) {
+		return nil
+	}
+	return &conflict.Conflict{
+		Path:    *field.Child("domain", "features", "smm"),
+		Message: secureBootRequiresSMMErr,
+	}
+}
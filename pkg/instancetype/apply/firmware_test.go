@@ -0,0 +1,116 @@
+package apply_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+	"kubevirt.io/kubevirt/pkg/libvmi"
+	"kubevirt.io/kubevirt/pkg/pointer"
+)
+
+var _ = Describe("Preference.Firmware SecureBoot", func() {
+	var (
+		vmi            *virtv1.VirtualMachineInstance
+		preferenceSpec *v1beta1.VirtualMachinePreferenceSpec
+
+		vmiApplier = apply.NewVMIApplier()
+		field      = k8sfield.NewPath("spec", "template", "spec")
+	)
+
+	BeforeEach(func() {
+		vmi = libvmi.New()
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			Firmware: &v1beta1.FirmwarePreferences{
+				PreferredEfi: &virtv1.EFI{},
+			},
+		}
+	})
+
+	It("should apply EFI without SecureBoot and leave SMM untouched", func() {
+		preferenceSpec.Firmware.PreferredEfi.SecureBoot = pointer.P(false)
+
+		Expect(vmiApplier.ApplyToVMI(field, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.Firmware.Bootloader.EFI).To(HaveValue(Equal(virtv1.EFI{SecureBoot: pointer.P(false)})))
+		Expect(vmi.Spec.Domain.Features).To(BeNil())
+	})
+
+	DescribeTable("should enable SMM when applying EFI with SecureBoot", func(secureBoot *bool) {
+		preferenceSpec.Firmware.PreferredEfi.SecureBoot = secureBoot
+
+		Expect(vmiApplier.ApplyToVMI(field, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.Features.SMM).To(HaveValue(Equal(virtv1.FeatureState{Enabled: pointer.P(true)})))
+	},
+		Entry("explicitly enabled", pointer.P(true)),
+		Entry("enabled by default", nil),
+	)
+
+	It("should enable SMM when applying SecureBoot through the deprecated preferences", func() {
+		preferenceSpec.Firmware = &v1beta1.FirmwarePreferences{
+			DeprecatedPreferredUseEfi:        pointer.P(true),
+			DeprecatedPreferredUseSecureBoot: pointer.P(true),
+		}
+
+		Expect(vmiApplier.ApplyToVMI(field, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.Features.SMM).To(HaveValue(Equal(virtv1.FeatureState{Enabled: pointer.P(true)})))
+	})
+
+	DescribeTable("should keep SMM already enabled by the VMI", func(smm virtv1.FeatureState) {
+		vmi.Spec.Domain.Features = &virtv1.Features{SMM: smm.DeepCopy()}
+
+		Expect(vmiApplier.ApplyToVMI(field, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.Features.SMM).To(HaveValue(Equal(smm)))
+	},
+		Entry("explicitly", virtv1.FeatureState{Enabled: pointer.P(true)}),
+		Entry("by default", virtv1.FeatureState{}),
+	)
+
+	It("should return a conflict when SMM is disabled by the VMI", func() {
+		vmi.Spec.Domain.Features = &virtv1.Features{
+			SMM: &virtv1.FeatureState{
+				Enabled: pointer.P(false),
+			},
+		}
+
+		conflicts := vmiApplier.ApplyToVMI(field, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.features.smm"))
+		Expect(conflicts[0].Error()).To(Equal("EFI SecureBoot provided by the preference requires SMM, which is disabled"))
+		Expect(conflicts[0].Source).To(Equal(conflict.SourcePreference))
+		Expect(conflicts[0].Describe()).To(Equal("spec.template.spec.domain.features.smm (preference)"))
+	})
+
+	It("should return a conflict when SMM is disabled by the preference", func() {
+		preferenceSpec.Features = &v1beta1.FeaturePreferences{
+			PreferredSmm: &virtv1.FeatureState{
+				Enabled: pointer.P(false),
+			},
+		}
+
+		conflicts := vmiApplier.ApplyToVMI(field, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.features.smm"))
+	})
+
+	It("should not return a conflict when SecureBoot is provided by the VMI", func() {
+		vmi.Spec.Domain.Firmware = &virtv1.Firmware{
+			Bootloader: &virtv1.Bootloader{
+				EFI: &virtv1.EFI{},
+			},
+		}
+		vmi.Spec.Domain.Features = &virtv1.Features{
+			SMM: &virtv1.FeatureState{
+				Enabled: pointer.P(false),
+			},
+		}
+
+		Expect(vmiApplier.ApplyToVMI(field, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.Features.SMM.Enabled).To(HaveValue(BeFalse()))
+	})
+})
@@ -19,6 +19,8 @@
 package apply
 
 import (
+	"k8s.io/apimachinery/pkg/api/equality"
+
 	virtv1 "kubevirt.io/api/core/v1"
 	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
 
@@ -26,6 +28,7 @@ import (
 )
 
 func applyGPUs(
+	opts *applyOptions,
 	baseConflict *conflict.Conflict,
 	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
 	vmiSpec *virtv1.VirtualMachineInstanceSpec,
@@ -35,11 +38,87 @@ func applyGPUs(
 	}
 
 	if len(vmiSpec.Domain.Devices.GPUs) > 0 {
-		return conflict.Conflicts{baseConflict.NewChild("domain", "devices", "gpus")}
+		// Accept GPUs already applied by the instancetype so that applying to an already expanded VMI is a no-op
+		if equality.Semantic.DeepEqual(vmiSpec.Domain.Devices.GPUs, instancetypeSpec.GPUs) {
+			return nil
+		}
+		if opts.mergeGPUs {
+			return mergeGPUs(opts, baseConflict, instancetypeSpec, vmiSpec)
+		}
+		return opts.resolveConflicts(baseConflict.NewChild("domain", "devices", "gpus"))
 	}
 
 	vmiSpec.Domain.Devices.GPUs = make([]virtv1.GPU, len(instancetypeSpec.GPUs))
-	copy(vmiSpec.Domain.Devices.GPUs, instancetypeSpec.GPUs)
+	for i := range instancetypeSpec.GPUs {
+		instancetypeSpec.GPUs[i].DeepCopyInto(&vmiSpec.Domain.Devices.GPUs[i])
+	}
 
 	return nil
 }
+
+// mergeGPUs appends each instancetype GPU not already present within the VMI in the order of the instancetype,
+// matching GPUs by name.
+// Identical GPUs are accepted while those sharing a name but differing in config are reported as conflicts.
+// The vGPU display options of the instancetype are applied to a matching VMI GPU not providing its own.
+func mergeGPUs(
+	opts *applyOptions,
+	baseConflict *conflict.Conflict,
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+) conflict.Conflicts {
+	vmiGPUs := make(map[string]int, len(vmiSpec.Domain.Devices.GPUs))
+	for i, gpu := range vmiSpec.Domain.Devices.GPUs {
+		vmiGPUs[gpu.Name] = i
+	}
+
+	var conflicts conflict.Conflicts
+	for _, instancetypeGPU := range instancetypeSpec.GPUs {
+		i, exists := vmiGPUs[instancetypeGPU.Name]
+		if !exists {
+			vmiSpec.Domain.Devices.GPUs = append(vmiSpec.Domain.Devices.GPUs, *instancetypeGPU.DeepCopy())
+			continue
+		}
+		gpuConflict := baseConflict.Child("domain", "devices", "gpus").Index(i)
+		vmiGPU := &vmiSpec.Domain.Devices.GPUs[i]
+		if !equality.Semantic.DeepEqual(withoutGPUDisplay(instancetypeGPU), withoutGPUDisplay(*vmiGPU)) {
+			conflicts = append(conflicts, conflict.NewFromPath(gpuConflict))
+			continue
+		}
+		if !applyGPUDisplay(instancetypeGPU, vmiGPU) {
+			conflicts = append(conflicts, conflict.NewFromPath(gpuConflict.Child("virtualGPUOptions", "display")))
+		}
+	}
+
+	return opts.resolveConflicts(conflicts...)
+}
+
+// applyGPUDisplay applies the vGPU display options of the instancetype GPU to the VMI GPU,
+// returning false when the VMI GPU already provides differing display options.
+func applyGPUDisplay(instancetypeGPU virtv1.GPU, vmiGPU *virtv1.GPU) bool {
+	instancetypeDisplay := gpuDisplay(instancetypeGPU)
+	vmiDisplay := gpuDisplay(*vmiGPU)
+	if instancetypeDisplay == nil {
+		return true
+	}
+	if vmiDisplay != nil {
+		return equality.Semantic.DeepEqual(instancetypeDisplay, vmiDisplay)
+	}
+	if vmiGPU.VirtualGPUOptions == nil {
+		vmiGPU.VirtualGPUOptions = &virtv1.VGPUOptions{}
+	}
+	vmiGPU.VirtualGPUOptions.Display = instancetypeDisplay.DeepCopy()
+	return true
+}
+
+func gpuDisplay(gpu virtv1.GPU) *virtv1.VGPUDisplayOptions {
+	if gpu.VirtualGPUOptions == nil {
+		return nil
+	}
+	return gpu.VirtualGPUOptions.Display
+}
+
+// withoutGPUDisplay returns a copy of the GPU without vGPU display options so that the remaining config can be compared
+func withoutGPUDisplay(gpu virtv1.GPU) virtv1.GPU {
+	gpu.VirtualGPUOptions = nil
+	return gpu
+}
@@ -12,6 +12,7 @@ import (
 
 	"kubevirt.io/kubevirt/pkg/instancetype/apply"
 	"kubevirt.io/kubevirt/pkg/libvmi"
+	"kubevirt.io/kubevirt/pkg/pointer"
 )
 
 var _ = Describe("instancetype.Spec.GPUs", func() {
@@ -53,4 +54,154 @@ var _ = Describe("instancetype.Spec.GPUs", func() {
 		Expect(conflicts).To(HaveLen(1))
 		Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.devices.gpus"))
 	})
+
+	Context("with vGPU display options", func() {
+		var displayInstancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec
+
+		BeforeEach(func() {
+			displayInstancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+				GPUs: []virtv1.GPU{
+					{
+						Name:       "barfoo",
+						DeviceName: "vendor.com/gpu_name",
+						VirtualGPUOptions: &virtv1.VGPUOptions{
+							Display: &virtv1.VGPUDisplayOptions{
+								Enabled: pointer.P(true),
+								RamFB:   &virtv1.FeatureState{Enabled: pointer.P(false)},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("should apply to VMI without sharing the display options of the instancetype", func() {
+			Expect(vmiApplier.ApplyToVMI(field, displayInstancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+			Expect(vmi.Spec.Domain.Devices.GPUs).To(Equal(displayInstancetypeSpec.GPUs))
+			Expect(vmi.Spec.Domain.Devices.GPUs[0].VirtualGPUOptions).ToNot(
+				BeIdenticalTo(displayInstancetypeSpec.GPUs[0].VirtualGPUOptions))
+		})
+
+		Context("WithGPUMerge", func() {
+			var mergeApplier = apply.NewVMIApplier(apply.WithGPUMerge())
+
+			DescribeTable("should apply the display options to a matching GPU", func(vmiOptions *virtv1.VGPUOptions) {
+				vmi.Spec.Domain.Devices.GPUs = []virtv1.GPU{{
+					Name:              "barfoo",
+					DeviceName:        "vendor.com/gpu_name",
+					VirtualGPUOptions: vmiOptions,
+				}}
+
+				Expect(mergeApplier.ApplyToVMI(field, displayInstancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+				Expect(vmi.Spec.Domain.Devices.GPUs).To(Equal(displayInstancetypeSpec.GPUs))
+			},
+				Entry("without vGPU options", nil),
+				Entry("without display options", &virtv1.VGPUOptions{}),
+			)
+
+			It("should accept a matching GPU with identical display options", func() {
+				vmi.Spec.Domain.Devices.GPUs = []virtv1.GPU{*displayInstancetypeSpec.GPUs[0].DeepCopy()}
+
+				Expect(mergeApplier.ApplyToVMI(field, displayInstancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+				Expect(vmi.Spec.Domain.Devices.GPUs).To(Equal(displayInstancetypeSpec.GPUs))
+			})
+
+			It("should keep the display options of a matching GPU when the instancetype provides none", func() {
+				vmiGPU := *displayInstancetypeSpec.GPUs[0].DeepCopy()
+				displayInstancetypeSpec.GPUs[0].VirtualGPUOptions = nil
+				vmi.Spec.Domain.Devices.GPUs = []virtv1.GPU{vmiGPU}
+
+				Expect(mergeApplier.ApplyToVMI(field, displayInstancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+				Expect(vmi.Spec.Domain.Devices.GPUs).To(Equal([]virtv1.GPU{vmiGPU}))
+			})
+
+			It("should detect differing display options per GPU", func() {
+				displayInstancetypeSpec.GPUs = append(displayInstancetypeSpec.GPUs, virtv1.GPU{
+					Name:       "foobar",
+					DeviceName: "vendor.com/gpu_name",
+					VirtualGPUOptions: &virtv1.VGPUOptions{
+						Display: &virtv1.VGPUDisplayOptions{Enabled: pointer.P(true)},
+					},
+				})
+				vmi.Spec.Domain.Devices.GPUs = []virtv1.GPU{
+					{
+						Name:       "barfoo",
+						DeviceName: "vendor.com/gpu_name",
+					},
+					{
+						Name:       "foobar",
+						DeviceName: "vendor.com/gpu_name",
+						VirtualGPUOptions: &virtv1.VGPUOptions{
+							Display: &virtv1.VGPUDisplayOptions{Enabled: pointer.P(false)},
+						},
+					},
+				}
+
+				conflicts := mergeApplier.ApplyToVMI(field, displayInstancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+				Expect(conflicts).To(HaveLen(1))
+				Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.devices.gpus[1].virtualGPUOptions.display"))
+			})
+		})
+	})
+
+	Context("WithGPUMerge", func() {
+		var mergeApplier = apply.NewVMIApplier(apply.WithGPUMerge())
+
+		It("should append disjoint GPUs", func() {
+			vmiGPU := virtv1.GPU{
+				Name:       "foobar",
+				DeviceName: "vendor.com/gpu_name",
+			}
+			vmi.Spec.Domain.Devices.GPUs = []virtv1.GPU{vmiGPU}
+
+			Expect(mergeApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.GPUs).To(Equal(append([]virtv1.GPU{vmiGPU}, instancetypeSpec.GPUs...)))
+		})
+
+		It("should accept identical GPUs", func() {
+			vmi.Spec.Domain.Devices.GPUs = []virtv1.GPU{*instancetypeSpec.GPUs[0].DeepCopy()}
+
+			Expect(mergeApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.GPUs).To(Equal(instancetypeSpec.GPUs))
+		})
+
+		It("should apply GPUs in the order of the instancetype across repeated applies", func() {
+			unsortedInstancetypeSpec := &v1beta1.VirtualMachineInstancetypeSpec{
+				GPUs: []virtv1.GPU{
+					{Name: "zulu", DeviceName: "vendor.com/zulu"},
+					{Name: "alpha", DeviceName: "vendor.com/alpha"},
+					{Name: "mike", DeviceName: "vendor.com/mike"},
+				},
+			}
+			vmiGPU := virtv1.GPU{
+				Name:       "foobar",
+				DeviceName: "vendor.com/gpu_name",
+			}
+			vmi.Spec.Domain.Devices.GPUs = []virtv1.GPU{vmiGPU}
+			otherVMI := vmi.DeepCopy()
+
+			Expect(mergeApplier.ApplyToVMI(field, unsortedInstancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(mergeApplier.ApplyToVMI(field, unsortedInstancetypeSpec, preferenceSpec, &otherVMI.Spec, &otherVMI.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.GPUs).To(Equal(append([]virtv1.GPU{vmiGPU}, unsortedInstancetypeSpec.GPUs...)))
+			Expect(otherVMI.Spec).To(Equal(vmi.Spec))
+		})
+
+		It("should detect GPUs sharing a name with differing configs", func() {
+			vmi.Spec.Domain.Devices.GPUs = []virtv1.GPU{
+				{
+					Name:       "foobar",
+					DeviceName: "vendor.com/gpu_name",
+				},
+				{
+					Name:       "barfoo",
+					DeviceName: "vendor.com/other_gpu_name",
+				},
+			}
+
+			conflicts := mergeApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.devices.gpus[1]"))
+		})
+	})
 })
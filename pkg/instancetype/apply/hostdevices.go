@@ -19,6 +19,10 @@
 package apply
 
 import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
 	virtv1 "kubevirt.io/api/core/v1"
 	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
 
@@ -26,6 +30,7 @@ import (
 )
 
 func applyHostDevices(
+	opts *applyOptions,
 	baseConflict *conflict.Conflict,
 	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
 	vmiSpec *virtv1.VirtualMachineInstanceSpec,
@@ -35,11 +40,66 @@ func applyHostDevices(
 	}
 
 	if len(vmiSpec.Domain.Devices.HostDevices) > 0 {
-		return conflict.Conflicts{baseConflict.NewChild("domain", "devices", "hostDevices")}
+		// Accept host devices already applied by the instancetype so that applying to an already expanded VMI is a no-op
+		if equality.Semantic.DeepEqual(vmiSpec.Domain.Devices.HostDevices, instancetypeSpec.HostDevices) {
+			return nil
+		}
+		if opts.mergeHostDevices {
+			return mergeHostDevices(opts, baseConflict, instancetypeSpec, vmiSpec)
+		}
+		return opts.resolveConflicts(baseConflict.NewChild("domain", "devices", "hostDevices"))
 	}
 
 	vmiSpec.Domain.Devices.HostDevices = make([]virtv1.HostDevice, len(instancetypeSpec.HostDevices))
-	copy(vmiSpec.Domain.Devices.HostDevices, instancetypeSpec.HostDevices)
+	for i := range instancetypeSpec.HostDevices {
+		instancetypeSpec.HostDevices[i].DeepCopyInto(&vmiSpec.Domain.Devices.HostDevices[i])
+	}
 
 	return nil
 }
+
+// mergeHostDevices appends each instancetype host device not already present within the VMI in the order of the instancetype,
+// matching host devices by name.
+// Identical host devices are accepted while those sharing a name but differing in config are reported as conflicts.
+func mergeHostDevices(
+	opts *applyOptions,
+	baseConflict *conflict.Conflict,
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+) conflict.Conflicts {
+	vmiHostDevices := make(map[string]int, len(vmiSpec.Domain.Devices.HostDevices))
+	for i, hostDevice := range vmiSpec.Domain.Devices.HostDevices {
+		vmiHostDevices[hostDevice.Name] = i
+	}
+
+	var conflicts conflict.Conflicts
+	for _, instancetypeHostDevice := range instancetypeSpec.HostDevices {
+		i, exists := vmiHostDevices[instancetypeHostDevice.Name]
+		if !exists {
+			vmiSpec.Domain.Devices.HostDevices = append(vmiSpec.Domain.Devices.HostDevices, *instancetypeHostDevice.DeepCopy())
+			continue
+		}
+		vmiHostDevice := vmiSpec.Domain.Devices.HostDevices[i]
+		if equality.Semantic.DeepEqual(instancetypeHostDevice, vmiHostDevice) {
+			continue
+		}
+		conflicts = append(conflicts, &conflict.Conflict{
+			Path:    *baseConflict.Child("domain", "devices", "hostDevices").Index(i),
+			Message: hostDeviceMismatchMessage(instancetypeHostDevice, vmiHostDevice),
+		})
+	}
+
+	return opts.resolveConflicts(conflicts...)
+}
+
+const hostDeviceMismatchErrFmt = "host device %s provided by the instance type has %s %q but is already defined by the VM with %s %q"
+
+// hostDeviceMismatchMessage names the field differing between two host devices sharing a name,
+// the deviceName when both differ.
+func hostDeviceMismatchMessage(instancetypeHostDevice, vmiHostDevice virtv1.HostDevice) string {
+	field, instancetypeValue, vmiValue := "deviceName", instancetypeHostDevice.DeviceName, vmiHostDevice.DeviceName
+	if instancetypeValue == vmiValue {
+		field, instancetypeValue, vmiValue = "tag", instancetypeHostDevice.Tag, vmiHostDevice.Tag
+	}
+	return fmt.Sprintf(hostDeviceMismatchErrFmt, instancetypeHostDevice.Name, field, instancetypeValue, field, vmiValue)
+}
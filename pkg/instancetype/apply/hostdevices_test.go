@@ -41,7 +41,7 @@ var _ = Describe("instancetype.Spec.HostDevices", func() {
 		Expect(vmi.Spec.Domain.Devices.HostDevices).To(Equal(instancetypeSpec.HostDevices))
 	})
 
-	It("should detect HostDevice conflict", func() {
+	It("should accept identical HostDevices", func() {
 		vmi.Spec.Domain.Devices.HostDevices = []virtv1.HostDevice{
 			{
 				Name:       "foobar",
@@ -49,8 +49,83 @@ var _ = Describe("instancetype.Spec.HostDevices", func() {
 			},
 		}
 
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.Devices.HostDevices).To(Equal(instancetypeSpec.HostDevices))
+	})
+
+	It("should detect HostDevice conflict", func() {
+		vmi.Spec.Domain.Devices.HostDevices = []virtv1.HostDevice{
+			{
+				Name:       "barfoo",
+				DeviceName: "vendor.com/other_device_name",
+			},
+		}
+
 		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
 		Expect(conflicts).To(HaveLen(1))
-		Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.devices.hostDevices"))
+		Expect(conflicts[0].Path.String()).To(Equal("spec.template.spec.domain.devices.hostDevices"))
+	})
+
+	Context("WithHostDeviceMerge", func() {
+		var mergeApplier = apply.NewVMIApplier(apply.WithHostDeviceMerge())
+
+		It("should append HostDevices not already provided by the VMI", func() {
+			vmiHostDevice := virtv1.HostDevice{
+				Name:       "barfoo",
+				DeviceName: "vendor.com/other_device_name",
+			}
+			vmi.Spec.Domain.Devices.HostDevices = []virtv1.HostDevice{vmiHostDevice}
+
+			Expect(mergeApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.HostDevices).To(Equal(append([]virtv1.HostDevice{vmiHostDevice}, instancetypeSpec.HostDevices...)))
+		})
+
+		It("should apply HostDevices in the order of the instancetype across repeated applies", func() {
+			unsortedInstancetypeSpec := &v1beta1.VirtualMachineInstancetypeSpec{
+				HostDevices: []virtv1.HostDevice{
+					{Name: "zulu", DeviceName: "vendor.com/zulu"},
+					{Name: "alpha", DeviceName: "vendor.com/alpha"},
+					{Name: "mike", DeviceName: "vendor.com/mike"},
+				},
+			}
+			vmiHostDevice := virtv1.HostDevice{
+				Name:       "barfoo",
+				DeviceName: "vendor.com/other_device_name",
+			}
+			vmi.Spec.Domain.Devices.HostDevices = []virtv1.HostDevice{vmiHostDevice}
+			otherVMI := vmi.DeepCopy()
+
+			Expect(mergeApplier.ApplyToVMI(field, unsortedInstancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(mergeApplier.ApplyToVMI(field, unsortedInstancetypeSpec, preferenceSpec, &otherVMI.Spec, &otherVMI.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.HostDevices).To(Equal(
+				append([]virtv1.HostDevice{vmiHostDevice}, unsortedInstancetypeSpec.HostDevices...)))
+			Expect(otherVMI.Spec).To(Equal(vmi.Spec))
+		})
+
+		DescribeTable("should detect HostDevices sharing a name with differing configs",
+			func(vmiHostDevice virtv1.HostDevice, expectedMessage string) {
+				vmi.Spec.Domain.Devices.HostDevices = []virtv1.HostDevice{
+					{
+						Name:       "barfoo",
+						DeviceName: "vendor.com/other_device_name",
+					},
+					vmiHostDevice,
+				}
+
+				conflicts := mergeApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+				Expect(conflicts).To(HaveLen(1))
+				Expect(conflicts[0].Path.String()).To(Equal("spec.template.spec.domain.devices.hostDevices[1]"))
+				Expect(conflicts[0].Error()).To(Equal(expectedMessage))
+			},
+			Entry("with a differing deviceName",
+				virtv1.HostDevice{Name: "foobar", DeviceName: "vendor.com/other_device_name"},
+				`host device foobar provided by the instance type has deviceName "vendor.com/device_name" `+
+					`but is already defined by the VM with deviceName "vendor.com/other_device_name"`,
+			),
+			Entry("with a differing tag",
+				virtv1.HostDevice{Name: "foobar", DeviceName: "vendor.com/device_name", Tag: "tag"},
+				`host device foobar provided by the instance type has tag "" but is already defined by the VM with tag "tag"`,
+			),
+		)
 	})
 })
@@ -0,0 +1,114 @@
+package apply_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/libvmi"
+	"kubevirt.io/kubevirt/pkg/pointer"
+)
+
+var _ = Describe("Applying to an already expanded VMI", func() {
+	var (
+		vmi              *virtv1.VirtualMachineInstance
+		instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec
+		preferenceSpec   *v1beta1.VirtualMachinePreferenceSpec
+
+		field = k8sfield.NewPath("spec", "template", "spec")
+	)
+
+	BeforeEach(func() {
+		vmi = libvmi.New(
+			libvmi.WithContainerDisk("disk", "image"),
+			libvmi.WithInterface(libvmi.InterfaceDeviceWithMasqueradeBinding()),
+			libvmi.WithNetwork(virtv1.DefaultPodNetwork()),
+		)
+
+		ioThreadsPolicy := virtv1.IOThreadsPolicyShared
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			CPU: v1beta1.CPUInstancetype{
+				Guest:                 uint32(8),
+				Model:                 pointer.P("host-passthrough"),
+				DedicatedCPUPlacement: pointer.P(true),
+				IsolateEmulatorThread: pointer.P(true),
+				NUMA: &virtv1.NUMA{
+					GuestMappingPassthrough: &virtv1.NUMAGuestMappingPassthrough{},
+				},
+				Realtime:   &virtv1.Realtime{Mask: "0-3"},
+				MaxSockets: pointer.P(uint32(2)),
+			},
+			Memory: v1beta1.MemoryInstancetype{
+				Guest:             resource.MustParse("2Gi"),
+				Hugepages:         &virtv1.Hugepages{PageSize: "2Mi"},
+				MaxGuest:          pointer.P(resource.MustParse("4Gi")),
+				OvercommitPercent: 10,
+			},
+			NodeSelector:    map[string]string{"key": "value"},
+			SchedulerName:   "scheduler",
+			IOThreadsPolicy: &ioThreadsPolicy,
+			LaunchSecurity: &virtv1.LaunchSecurity{
+				SEV: &virtv1.SEV{Session: "session", DHCert: "cert"},
+			},
+			GPUs:        []virtv1.GPU{{Name: "gpu", DeviceName: "vendor.com/gpu"}},
+			HostDevices: []virtv1.HostDevice{{Name: "hostdevice", DeviceName: "vendor.com/hostdevice"}},
+			Annotations: map[string]string{"annotation": "value"},
+		}
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			CPU: &v1beta1.CPUPreferences{
+				PreferredCPUTopology: pointer.P(v1beta1.Spread),
+				PreferredCPUFeatures: []virtv1.CPUFeature{{Name: "feature", Policy: "require"}},
+			},
+			Devices: &v1beta1.DevicePreferences{
+				PreferredDiskBus:        virtv1.DiskBusVirtio,
+				PreferredInterfaceModel: virtv1.VirtIO,
+				PreferredRng:            &virtv1.Rng{},
+			},
+			Firmware: &v1beta1.FirmwarePreferences{
+				PreferredEfi: &virtv1.EFI{SecureBoot: pointer.P(true)},
+			},
+			Features: &v1beta1.FeaturePreferences{
+				PreferredSmm: &virtv1.FeatureState{Enabled: pointer.P(true)},
+			},
+			Machine: &v1beta1.MachinePreferences{
+				PreferredMachineType: "q35",
+			},
+			Annotations: map[string]string{"preference-annotation": "value"},
+		}
+	})
+
+	DescribeTable("should neither conflict nor mutate the VMI", func(opts ...apply.Option) {
+		vmiApplier := apply.NewVMIApplier(opts...)
+		instancetypeSource := &apply.AppliedSource{Labels: map[string]string{"label": "value"}}
+
+		result := vmiApplier.ApplyToVMIWithResult(field, instancetypeSource, instancetypeSpec, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(result.Conflicts).To(BeEmpty())
+		expandedVMI := vmi.DeepCopy()
+
+		result = vmiApplier.ApplyToVMIWithResult(field, instancetypeSource, instancetypeSpec, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(result.Conflicts).To(BeEmpty())
+		Expect(vmi).To(Equal(expandedVMI))
+	},
+		Entry("without options"),
+		Entry("with GPU merge", apply.WithGPUMerge()),
+		Entry("with host device merge", apply.WithHostDeviceMerge()),
+	)
+
+	It("should still conflict when a value applied by the instancetype has since been changed", func() {
+		vmiApplier := apply.NewVMIApplier()
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+		vmi.Spec.Domain.CPU.Cores++
+		vmi.Spec.SchedulerName = "other-scheduler"
+
+		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts.String()).To(Equal("spec.template.spec.domain.cpu.cores, spec.template.spec.domain.cpu.sockets, " +
+			"spec.template.spec.domain.cpu.threads, spec.template.spec.schedulerName"))
+	})
+})
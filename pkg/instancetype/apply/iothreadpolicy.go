@@ -19,6 +19,10 @@
 package apply
 
 import (
+	"fmt"
+
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
 	virtv1 "kubevirt.io/api/core/v1"
 	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
 
@@ -26,6 +30,7 @@ import (
 )
 
 func applyIOThreadPolicy(
+	opts *applyOptions,
 	baseConflict *conflict.Conflict,
 	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
 	vmiSpec *virtv1.VirtualMachineInstanceSpec,
@@ -35,7 +40,19 @@ func applyIOThreadPolicy(
 	}
 
 	if vmiSpec.Domain.IOThreadsPolicy != nil {
-		return conflict.Conflicts{baseConflict.NewChild("domain", "ioThreadsPolicy")}
+		// Accept a policy already applied by the instancetype so that applying to an already expanded VMI is a no-op
+		if *vmiSpec.Domain.IOThreadsPolicy == *instancetypeSpec.IOThreadsPolicy {
+			return nil
+		}
+		return opts.resolveConflicts(baseConflict.NewChild("domain", "ioThreadsPolicy"))
+	}
+
+	if countConflict := validateIOThreadCount(instancetypeSpec, vmiSpec); countConflict != nil {
+		return conflict.Conflicts{countConflict}
+	}
+
+	if diskConflicts := validateDedicatedIOThreadDisks(baseConflict, instancetypeSpec, vmiSpec); len(diskConflicts) > 0 {
+		return diskConflicts
 	}
 
 	instancetypeIOThreadPolicy := *instancetypeSpec.IOThreadsPolicy
@@ -43,3 +60,95 @@ func applyIOThreadPolicy(
 
 	return nil
 }
+
+const (
+	instancetypeIOThreadsPolicyPath           = "instancetype.spec.ioThreadsPolicy"
+	ioThreadCountWithIncompatiblePolicyErrFmt = "supplementalPoolThreadCount %d provided by the VMI requires the %s ioThreadsPolicy " +
+		"but the instance type provides the %s ioThreadsPolicy"
+	ioThreadPolicyWithoutCountErrFmt = "the %s ioThreadsPolicy provided by the instance type requires a positive " +
+		"supplementalPoolThreadCount to be provided by the VMI"
+)
+
+// validateIOThreadCount ensures any dedicated IOThread count provided by the VMI is compatible with the policy of the instancetype
+func validateIOThreadCount(instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec, vmiSpec *virtv1.VirtualMachineInstanceSpec) *conflict.Conflict {
+	var count *uint32
+	if vmiSpec.Domain.IOThreads != nil {
+		count = vmiSpec.Domain.IOThreads.SupplementalPoolThreadCount
+	}
+
+	policy := *instancetypeSpec.IOThreadsPolicy
+	if policy == virtv1.IOThreadsPolicySupplementalPool && (count == nil || *count < 1) {
+		return conflict.NewWithMessage(fmt.Sprintf(ioThreadPolicyWithoutCountErrFmt, policy), instancetypeIOThreadsPolicyPath)
+	}
+	if policy != virtv1.IOThreadsPolicySupplementalPool && count != nil {
+		return conflict.NewWithMessage(
+			fmt.Sprintf(ioThreadCountWithIncompatiblePolicyErrFmt, *count, virtv1.IOThreadsPolicySupplementalPool, policy),
+			instancetypeIOThreadsPolicyPath,
+		)
+	}
+	return nil
+}
+
+const dedicatedIOThreadWithSupplementalPoolErrFmt = "dedicatedIOThread requested by disk %s can not be provided " +
+	"with the %s ioThreadsPolicy provided by the instance type"
+
+// validateDedicatedIOThreadDisks ensures disks requesting a dedicated IOThread use a policy able to provide one,
+// as the supplementalPool policy shares a pool of IOThreads between all disks.
+func validateDedicatedIOThreadDisks(
+	baseConflict *conflict.Conflict,
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+) conflict.Conflicts {
+	policy := *instancetypeSpec.IOThreadsPolicy
+	if policy != virtv1.IOThreadsPolicySupplementalPool {
+		return nil
+	}
+	var conflicts conflict.Conflicts
+	for i, disk := range vmiSpec.Domain.Devices.Disks {
+		if disk.DedicatedIOThread == nil || !*disk.DedicatedIOThread {
+			continue
+		}
+		diskConflict := conflict.NewFromPath(baseConflict.Child("domain", "devices", "disks").Index(i).Child("dedicatedIOThread"))
+		diskConflict.Message = fmt.Sprintf(dedicatedIOThreadWithSupplementalPoolErrFmt, disk.Name, policy)
+		conflicts = append(conflicts, diskConflict)
+	}
+	return conflicts
+}
+
+const preferredDedicatedIOThreadWithSupplementalPoolErrFmt = "dedicatedIOThread provided by the preference for disk %s " +
+	"can not be provided with the %s ioThreadsPolicy"
+
+// disksDefiningDedicatedIOThread returns the names of the disks that already define whether to use a dedicated IOThread
+func disksDefiningDedicatedIOThread(vmiSpec *virtv1.VirtualMachineInstanceSpec) map[string]struct{} {
+	disks := map[string]struct{}{}
+	for _, disk := range vmiSpec.Domain.Devices.Disks {
+		if disk.DedicatedIOThread != nil {
+			disks[disk.Name] = struct{}{}
+		}
+	}
+	return disks
+}
+
+// checkPreferredDedicatedIOThreads returns a conflict for each disk given a dedicated IOThread by the preference
+// when the ioThreadsPolicy of the VMI is supplementalPool, which shares a pool of IOThreads between all disks.
+func checkPreferredDedicatedIOThreads(
+	field *k8sfield.Path,
+	definedByVMI map[string]struct{},
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+) conflict.Conflicts {
+	policy := vmiSpec.Domain.IOThreadsPolicy
+	if policy == nil || *policy != virtv1.IOThreadsPolicySupplementalPool {
+		return nil
+	}
+	var conflicts conflict.Conflicts
+	for i, disk := range vmiSpec.Domain.Devices.Disks {
+		if _, defined := definedByVMI[disk.Name]; defined || disk.DedicatedIOThread == nil || !*disk.DedicatedIOThread {
+			continue
+		}
+		conflicts = append(conflicts, &conflict.Conflict{
+			Path:    *field.Child("domain", "devices", "disks").Index(i).Child("dedicatedIOThread"),
+			Message: fmt.Sprintf(preferredDedicatedIOThreadWithSupplementalPoolErrFmt, disk.Name, *policy),
+		})
+	}
+	return conflicts
+}
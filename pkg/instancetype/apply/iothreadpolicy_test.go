@@ -10,7 +10,9 @@ import (
 	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
 
 	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
 	"kubevirt.io/kubevirt/pkg/libvmi"
+	"kubevirt.io/kubevirt/pkg/pointer"
 )
 
 var _ = Describe("instancetype.Spec.ioThreadsPolicy", func() {
@@ -37,10 +39,170 @@ var _ = Describe("instancetype.Spec.ioThreadsPolicy", func() {
 	})
 
 	It("should detect IOThreadsPolicy conflict", func() {
-		vmi.Spec.Domain.IOThreadsPolicy = &instancetypePolicy
+		vmiPolicy := virtv1.IOThreadsPolicyAuto
+		vmi.Spec.Domain.IOThreadsPolicy = &vmiPolicy
 
 		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
 		Expect(conflicts).To(HaveLen(1))
 		Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.ioThreadsPolicy"))
 	})
+
+	It("should accept an identical IOThreadsPolicy", func() {
+		vmiPolicy := instancetypePolicy
+		vmi.Spec.Domain.IOThreadsPolicy = &vmiPolicy
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.IOThreadsPolicy).To(HaveValue(Equal(instancetypePolicy)))
+	})
+
+	DescribeTable("should apply the policy with a compatible supplementalPoolThreadCount",
+		func(policy virtv1.IOThreadsPolicy, ioThreads *virtv1.DiskIOThreads) {
+			vmi.Spec.Domain.IOThreads = ioThreads
+			instancetypeSpec := &v1beta1.VirtualMachineInstancetypeSpec{
+				IOThreadsPolicy: pointer.P(policy),
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.IOThreadsPolicy).To(HaveValue(Equal(policy)))
+			Expect(vmi.Spec.Domain.IOThreads).To(Equal(ioThreads))
+		},
+		Entry("with the supplementalPool policy and a count",
+			virtv1.IOThreadsPolicySupplementalPool, &virtv1.DiskIOThreads{SupplementalPoolThreadCount: pointer.P(uint32(4))}),
+		Entry("with the shared policy and no count", virtv1.IOThreadsPolicyShared, nil),
+		Entry("with the auto policy and no count", virtv1.IOThreadsPolicyAuto, nil),
+		Entry("with the auto policy and IOThreads without a count", virtv1.IOThreadsPolicyAuto, &virtv1.DiskIOThreads{}),
+	)
+
+	Context("with disks requesting a dedicated IOThread", func() {
+		BeforeEach(func() {
+			vmi.Spec.Domain.IOThreads = &virtv1.DiskIOThreads{SupplementalPoolThreadCount: pointer.P(uint32(2))}
+			vmi.Spec.Domain.Devices.Disks = []virtv1.Disk{{
+				Name:              "shared",
+				DedicatedIOThread: pointer.P(false),
+			}, {
+				Name:              "dedicated",
+				DedicatedIOThread: pointer.P(true),
+			}, {
+				Name: "default",
+			}}
+		})
+
+		DescribeTable("should apply the policy", func(policy virtv1.IOThreadsPolicy) {
+			vmi.Spec.Domain.IOThreads = nil
+			instancetypeSpec := &v1beta1.VirtualMachineInstancetypeSpec{
+				IOThreadsPolicy: pointer.P(policy),
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.IOThreadsPolicy).To(HaveValue(Equal(policy)))
+			Expect(vmi.Spec.Domain.Devices.Disks[1].DedicatedIOThread).To(HaveValue(BeTrue()))
+		},
+			Entry("when shared", virtv1.IOThreadsPolicyShared),
+			Entry("when auto", virtv1.IOThreadsPolicyAuto),
+		)
+
+		It("should return a conflict for each disk when the policy is supplementalPool", func() {
+			vmi.Spec.Domain.Devices.Disks = append(vmi.Spec.Domain.Devices.Disks, virtv1.Disk{
+				Name:              "another-dedicated",
+				DedicatedIOThread: pointer.P(true),
+			})
+			instancetypeSpec := &v1beta1.VirtualMachineInstancetypeSpec{
+				IOThreadsPolicy: pointer.P(virtv1.IOThreadsPolicySupplementalPool),
+			}
+
+			conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(HaveLen(2))
+			Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.devices.disks[1].dedicatedIOThread"))
+			Expect(conflicts[0].Error()).To(Equal(
+				"dedicatedIOThread requested by disk dedicated can not be provided with the supplementalPool ioThreadsPolicy provided by the instance type"))
+			Expect(conflicts[1].String()).To(Equal("spec.template.spec.domain.devices.disks[3].dedicatedIOThread"))
+			Expect(conflicts[1].Error()).To(Equal(
+				"dedicatedIOThread requested by disk another-dedicated can not be provided with the supplementalPool ioThreadsPolicy " +
+					"provided by the instance type"))
+			Expect(vmi.Spec.Domain.IOThreadsPolicy).To(BeNil())
+		})
+	})
+
+	Context("with a preferred dedicated IOThread", func() {
+		BeforeEach(func() {
+			preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+				Devices: &v1beta1.DevicePreferences{
+					PreferredDiskBus:               virtv1.DiskBusVirtio,
+					PreferredDiskDedicatedIoThread: pointer.P(true),
+				},
+			}
+			vmi.Spec.Domain.IOThreads = &virtv1.DiskIOThreads{SupplementalPoolThreadCount: pointer.P(uint32(2))}
+			vmi.Spec.Domain.Devices.Disks = []virtv1.Disk{{
+				Name:              "defined",
+				DedicatedIOThread: pointer.P(false),
+			}, {
+				Name: "preferred",
+			}}
+		})
+
+		AfterEach(func() {
+			preferenceSpec = nil
+		})
+
+		DescribeTable("should apply to disks without the flag", func(policy virtv1.IOThreadsPolicy) {
+			vmi.Spec.Domain.IOThreadsPolicy = pointer.P(policy)
+
+			Expect(vmiApplier.ApplyToVMI(field, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.Disks[0].DedicatedIOThread).To(HaveValue(BeFalse()))
+			Expect(vmi.Spec.Domain.Devices.Disks[1].DedicatedIOThread).To(HaveValue(BeTrue()))
+		},
+			Entry("without a policy", virtv1.IOThreadsPolicy("")),
+			Entry("with the shared policy", virtv1.IOThreadsPolicyShared),
+			Entry("with the auto policy", virtv1.IOThreadsPolicyAuto),
+		)
+
+		It("should return a conflict for disks without the flag when the policy is supplementalPool", func() {
+			vmi.Spec.Domain.IOThreadsPolicy = pointer.P(virtv1.IOThreadsPolicySupplementalPool)
+
+			conflicts := vmiApplier.ApplyToVMI(field, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.devices.disks[1].dedicatedIOThread"))
+			Expect(conflicts[0].Error()).To(Equal(
+				"dedicatedIOThread provided by the preference for disk preferred can not be provided with the supplementalPool ioThreadsPolicy"))
+			Expect(conflicts[0].Source).To(Equal(conflict.SourcePreference))
+		})
+
+		It("should not return a conflict when every disk defines the flag", func() {
+			vmi.Spec.Domain.IOThreadsPolicy = pointer.P(virtv1.IOThreadsPolicySupplementalPool)
+			vmi.Spec.Domain.Devices.Disks[1].DedicatedIOThread = pointer.P(false)
+
+			Expect(vmiApplier.ApplyToVMI(field, nil, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.Disks[1].DedicatedIOThread).To(HaveValue(BeFalse()))
+		})
+	})
+
+	DescribeTable("should return a conflict with an incompatible supplementalPoolThreadCount",
+		func(policy virtv1.IOThreadsPolicy, ioThreads *virtv1.DiskIOThreads, expectedMessage string) {
+			vmi.Spec.Domain.IOThreads = ioThreads
+			instancetypeSpec := &v1beta1.VirtualMachineInstancetypeSpec{
+				IOThreadsPolicy: pointer.P(policy),
+			}
+
+			conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].String()).To(Equal("instancetype.spec.ioThreadsPolicy"))
+			Expect(conflicts[0].Error()).To(Equal(expectedMessage))
+			Expect(vmi.Spec.Domain.IOThreadsPolicy).To(BeNil())
+		},
+		Entry("with the supplementalPool policy and no count", virtv1.IOThreadsPolicySupplementalPool, nil,
+			"the supplementalPool ioThreadsPolicy provided by the instance type requires a positive "+
+				"supplementalPoolThreadCount to be provided by the VMI"),
+		Entry("with the supplementalPool policy and a count of 0",
+			virtv1.IOThreadsPolicySupplementalPool, &virtv1.DiskIOThreads{SupplementalPoolThreadCount: pointer.P(uint32(0))},
+			"the supplementalPool ioThreadsPolicy provided by the instance type requires a positive "+
+				"supplementalPoolThreadCount to be provided by the VMI"),
+		Entry("with the shared policy and a count",
+			virtv1.IOThreadsPolicyShared, &virtv1.DiskIOThreads{SupplementalPoolThreadCount: pointer.P(uint32(4))},
+			"supplementalPoolThreadCount 4 provided by the VMI requires the supplementalPool ioThreadsPolicy "+
+				"but the instance type provides the shared ioThreadsPolicy"),
+		Entry("with the auto policy and a count",
+			virtv1.IOThreadsPolicyAuto, &virtv1.DiskIOThreads{SupplementalPoolThreadCount: pointer.P(uint32(2))},
+			"supplementalPoolThreadCount 2 provided by the VMI requires the supplementalPool ioThreadsPolicy "+
+				"but the instance type provides the auto ioThreadsPolicy"),
+	)
 })
@@ -23,9 +23,11 @@ import (
 	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
 
 	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+	"kubevirt.io/kubevirt/pkg/pointer"
 )
 
 func applyLaunchSecurity(
+	opts *applyOptions,
 	baseConflict *conflict.Conflict,
 	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
 	vmiSpec *virtv1.VirtualMachineInstanceSpec,
@@ -34,11 +36,59 @@ func applyLaunchSecurity(
 		return nil
 	}
 
-	if vmiSpec.Domain.LaunchSecurity != nil {
-		return conflict.Conflicts{baseConflict.NewChild("domain", "launchSecurity")}
+	if vmiSpec.Domain.LaunchSecurity == nil {
+		vmiSpec.Domain.LaunchSecurity = instancetypeSpec.LaunchSecurity.DeepCopy()
+		return nil
+	}
+
+	// The VMI already provides launch security so merge each sub-field of the
+	// instancetype, only reporting a conflict for those defined differently by both.
+	if instancetypeSpec.LaunchSecurity.SEV == nil {
+		return nil
+	}
+
+	if vmiSpec.Domain.LaunchSecurity.SEV == nil {
+		vmiSpec.Domain.LaunchSecurity.SEV = instancetypeSpec.LaunchSecurity.SEV.DeepCopy()
+		return nil
+	}
+
+	return opts.resolveConflicts(
+		applySEV(baseConflict.NewChild("domain", "launchSecurity", "sev"), instancetypeSpec.LaunchSecurity.SEV, vmiSpec.Domain.LaunchSecurity.SEV)...,
+	)
+}
+
+func applySEV(sevConflict *conflict.Conflict, instancetypeSEV, vmiSEV *virtv1.SEV) (conflicts conflict.Conflicts) {
+	if instancetypeSEV.Policy != nil {
+		if vmiSEV.Policy == nil {
+			vmiSEV.Policy = instancetypeSEV.Policy.DeepCopy()
+		} else if instancetypeSEV.Policy.EncryptedState != nil {
+			if vmiSEV.Policy.EncryptedState == nil {
+				vmiSEV.Policy.EncryptedState = pointer.P(*instancetypeSEV.Policy.EncryptedState)
+			} else if *vmiSEV.Policy.EncryptedState != *instancetypeSEV.Policy.EncryptedState {
+				conflicts = append(conflicts, sevConflict.NewChild("policy", "encryptedState"))
+			}
+		}
+	}
+
+	if instancetypeSEV.Attestation != nil && vmiSEV.Attestation == nil {
+		vmiSEV.Attestation = instancetypeSEV.Attestation.DeepCopy()
+	}
+
+	if instancetypeSEV.Session != "" {
+		if vmiSEV.Session == "" {
+			vmiSEV.Session = instancetypeSEV.Session
+		} else if vmiSEV.Session != instancetypeSEV.Session {
+			conflicts = append(conflicts, sevConflict.NewChild("session"))
+		}
 	}
 
-	vmiSpec.Domain.LaunchSecurity = instancetypeSpec.LaunchSecurity.DeepCopy()
+	if instancetypeSEV.DHCert != "" {
+		if vmiSEV.DHCert == "" {
+			vmiSEV.DHCert = instancetypeSEV.DHCert
+		} else if vmiSEV.DHCert != instancetypeSEV.DHCert {
+			conflicts = append(conflicts, sevConflict.NewChild("dhCert"))
+		}
+	}
 
-	return nil
+	return conflicts
 }
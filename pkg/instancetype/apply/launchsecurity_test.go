@@ -10,7 +10,9 @@ import (
 	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
 
 	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
 	"kubevirt.io/kubevirt/pkg/libvmi"
+	"kubevirt.io/kubevirt/pkg/pointer"
 )
 
 var _ = Describe("instancetype.Spec.LaunchSecurity", func() {
@@ -37,13 +39,106 @@ var _ = Describe("instancetype.Spec.LaunchSecurity", func() {
 		Expect(vmi.Spec.Domain.LaunchSecurity).To(HaveValue(Equal(*instancetypeSpec.LaunchSecurity)))
 	})
 
-	It("should detect LaunchSecurity conflict", func() {
+	It("should apply SEV-ES to VMI", func() {
+		sevESInstancetypeSpec := &v1beta1.VirtualMachineInstancetypeSpec{
+			LaunchSecurity: &virtv1.LaunchSecurity{
+				SEV: &virtv1.SEV{
+					Policy: &virtv1.SEVPolicy{
+						EncryptedState: pointer.P(true),
+					},
+					Attestation: &virtv1.SEVAttestation{},
+				},
+			},
+		}
+
+		Expect(vmiApplier.ApplyToVMI(field, sevESInstancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.LaunchSecurity).To(HaveValue(Equal(*sevESInstancetypeSpec.LaunchSecurity)))
+	})
+
+	It("should merge SEV sub-fields not provided by the VMI", func() {
+		sevESInstancetypeSpec := &v1beta1.VirtualMachineInstancetypeSpec{
+			LaunchSecurity: &virtv1.LaunchSecurity{
+				SEV: &virtv1.SEV{
+					Policy: &virtv1.SEVPolicy{
+						EncryptedState: pointer.P(true),
+					},
+					Attestation: &virtv1.SEVAttestation{},
+					Session:     "session",
+				},
+			},
+		}
+		vmi.Spec.Domain.LaunchSecurity = &virtv1.LaunchSecurity{
+			SEV: &virtv1.SEV{
+				Policy:  &virtv1.SEVPolicy{},
+				Session: "session",
+				DHCert:  "dhCert",
+			},
+		}
+
+		Expect(vmiApplier.ApplyToVMI(field, sevESInstancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.LaunchSecurity).To(HaveValue(Equal(virtv1.LaunchSecurity{
+			SEV: &virtv1.SEV{
+				Policy: &virtv1.SEVPolicy{
+					EncryptedState: pointer.P(true),
+				},
+				Attestation: &virtv1.SEVAttestation{},
+				Session:     "session",
+				DHCert:      "dhCert",
+			},
+		})))
+	})
+
+	It("should apply SEV when the VMI provides no SEV", func() {
+		vmi.Spec.Domain.LaunchSecurity = &virtv1.LaunchSecurity{}
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.LaunchSecurity).To(HaveValue(Equal(*instancetypeSpec.LaunchSecurity)))
+	})
+
+	It("should detect SEV and SEV-ES conflict", func() {
+		sevESInstancetypeSpec := &v1beta1.VirtualMachineInstancetypeSpec{
+			LaunchSecurity: &virtv1.LaunchSecurity{
+				SEV: &virtv1.SEV{
+					Policy: &virtv1.SEVPolicy{
+						EncryptedState: pointer.P(true),
+					},
+				},
+			},
+		}
 		vmi.Spec.Domain.LaunchSecurity = &virtv1.LaunchSecurity{
-			SEV: &virtv1.SEV{},
+			SEV: &virtv1.SEV{
+				Policy: &virtv1.SEVPolicy{
+					EncryptedState: pointer.P(false),
+				},
+			},
+		}
+
+		conflicts := vmiApplier.ApplyToVMI(field, sevESInstancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(Equal(conflict.Conflicts{
+			conflict.New("spec", "template", "spec", "domain", "launchSecurity", "sev", "policy", "encryptedState"),
+		}.WithSource(conflict.SourceInstancetype)))
+	})
+
+	It("should detect each conflicting SEV sub-field", func() {
+		sevInstancetypeSpec := &v1beta1.VirtualMachineInstancetypeSpec{
+			LaunchSecurity: &virtv1.LaunchSecurity{
+				SEV: &virtv1.SEV{
+					Session: "instancetypeSession",
+					DHCert:  "instancetypeDHCert",
+				},
+			},
+		}
+		vmi.Spec.Domain.LaunchSecurity = &virtv1.LaunchSecurity{
+			SEV: &virtv1.SEV{
+				Session: "vmiSession",
+				DHCert:  "vmiDHCert",
+			},
 		}
 
-		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
-		Expect(conflicts).To(HaveLen(1))
-		Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.launchSecurity"))
+		conflicts := vmiApplier.ApplyToVMI(field, sevInstancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(Equal(conflict.Conflicts{
+			conflict.New("spec", "template", "spec", "domain", "launchSecurity", "sev", "dhCert"),
+			conflict.New("spec", "template", "spec", "domain", "launchSecurity", "sev", "session"),
+		}.WithSource(conflict.SourceInstancetype)))
 	})
 })
@@ -19,7 +19,11 @@
 package apply
 
 import (
+	"fmt"
+	"slices"
+
 	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	virtv1 "kubevirt.io/api/core/v1"
@@ -29,48 +33,208 @@ import (
 )
 
 func applyMemory(
+	opts *applyOptions,
 	baseConflict *conflict.Conflict,
 	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
 	vmiSpec *virtv1.VirtualMachineInstanceSpec,
 ) conflict.Conflicts {
+	// Accept memory already applied by the instancetype so that applying to an already expanded VMI is a no-op
+	if hasInstancetypeMemory(instancetypeSpec, vmiSpec) {
+		return nil
+	}
+
+	if instancetypeSpec.Memory.MaxGuest != nil && vmiSpec.Domain.Memory != nil && vmiSpec.Domain.Memory.MaxGuest != nil {
+		return opts.resolveConflicts(baseConflict.NewChild("domain", "memory", "maxGuest"))
+	}
+
 	if vmiSpec.Domain.Memory != nil {
-		return conflict.Conflicts{baseConflict.NewChild("domain", "memory")}
+		return opts.resolveConflicts(baseConflict.NewChild("domain", "memory"))
 	}
 
 	if _, hasMemoryRequests := vmiSpec.Domain.Resources.Requests[k8sv1.ResourceMemory]; hasMemoryRequests {
-		return conflict.Conflicts{baseConflict.NewChild("domain", "resources", "requests", string(k8sv1.ResourceMemory))}
+		return opts.resolveConflicts(baseConflict.NewChild("domain", "resources", "requests", string(k8sv1.ResourceMemory)))
 	}
 
-	if _, hasMemoryLimits := vmiSpec.Domain.Resources.Limits[k8sv1.ResourceMemory]; hasMemoryLimits {
-		return conflict.Conflicts{baseConflict.NewChild("domain", "resources", "limits", string(k8sv1.ResourceMemory))}
+	if memoryLimit, hasMemoryLimits := vmiSpec.Domain.Resources.Limits[k8sv1.ResourceMemory]; hasMemoryLimits {
+		if limitConflict := validateMemoryLimit(baseConflict, instancetypeSpec, memoryLimit); limitConflict != nil {
+			return opts.resolveConflicts(limitConflict)
+		}
 	}
 
-	instancetypeMemory := instancetypeSpec.Memory.Guest.DeepCopy()
-	vmiSpec.Domain.Memory = &virtv1.Memory{
-		Guest: &instancetypeMemory,
+	if hugepagesConflict := validateHugepages(instancetypeSpec); hugepagesConflict != nil {
+		return conflict.Conflicts{hugepagesConflict}
 	}
 
-	// If memory overcommit has been requested, set the memory requests to be
-	// lower than the guest memory by the requested percent.
-	const totalPercentage = 100
-	if instancetypeOverCommit := instancetypeSpec.Memory.OvercommitPercent; instancetypeOverCommit > 0 {
+	if maxGuestConflict := validateMaxGuest(instancetypeSpec); maxGuestConflict != nil {
+		return conflict.Conflicts{maxGuestConflict}
+	}
+
+	if overcommitConflict := validateOvercommitPercent(instancetypeSpec); overcommitConflict != nil {
+		return conflict.Conflicts{overcommitConflict}
+	}
+
+	vmiSpec.Domain.Memory = newInstancetypeMemory(instancetypeSpec)
+	warnGuestMemoryExceedsNodes(opts, baseConflict, instancetypeSpec)
+
+	if podRequestedMemory, overcommitted := overcommitMemoryRequest(instancetypeSpec); overcommitted {
 		if vmiSpec.Domain.Resources.Requests == nil {
 			vmiSpec.Domain.Resources.Requests = k8sv1.ResourceList{}
 		}
+		vmiSpec.Domain.Resources.Requests[k8sv1.ResourceMemory] = podRequestedMemory
+	}
 
-		podRequestedMemory := int64(float32(instancetypeMemory.Value()) * (1 - float32(instancetypeOverCommit)/totalPercentage))
+	return nil
+}
+
+const guestMemoryExceedsNodesWarningFmt = "guest memory %s provided by the instance type exceeds the allocatable memory " +
+	"of every schedulable node, the largest being %s"
 
-		vmiSpec.Domain.Resources.Requests[k8sv1.ResourceMemory] = *resource.NewQuantity(podRequestedMemory, instancetypeMemory.Format)
+// warnGuestMemoryExceedsNodes emits a warning when the guest memory of the instancetype exceeds the allocatable memory
+// of every node provided through WithNodeAllocatableMemory, as the VMI would then not be schedulable
+func warnGuestMemoryExceedsNodes(
+	opts *applyOptions,
+	baseConflict *conflict.Conflict,
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+) {
+	if len(opts.nodeAllocatableMemory) == 0 {
+		return
+	}
+	largest := slices.MaxFunc(opts.nodeAllocatableMemory, func(a, b resource.Quantity) int {
+		return a.Cmp(b)
+	})
+	if instancetypeSpec.Memory.Guest.Cmp(largest) <= 0 {
+		return
+	}
+	warning := baseConflict.NewChild("domain", "memory", "guest")
+	warning.Message = fmt.Sprintf(guestMemoryExceedsNodesWarningFmt, quantityString(instancetypeSpec.Memory.Guest), quantityString(largest))
+	opts.warn(warning)
+}
+
+func newInstancetypeMemory(instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec) *virtv1.Memory {
+	instancetypeMemory := instancetypeSpec.Memory.Guest.DeepCopy()
+	memory := &virtv1.Memory{
+		Guest: &instancetypeMemory,
 	}
 
 	if instancetypeSpec.Memory.Hugepages != nil {
-		vmiSpec.Domain.Memory.Hugepages = instancetypeSpec.Memory.Hugepages.DeepCopy()
+		memory.Hugepages = instancetypeSpec.Memory.Hugepages.DeepCopy()
 	}
 
 	if instancetypeSpec.Memory.MaxGuest != nil {
 		m := instancetypeSpec.Memory.MaxGuest.DeepCopy()
-		vmiSpec.Domain.Memory.MaxGuest = &m
+		memory.MaxGuest = &m
+	}
+
+	return memory
+}
+
+// overcommitMemoryRequest returns the memory request of the pod when memory overcommit has been requested,
+// lower than the guest memory by the requested percent.
+func overcommitMemoryRequest(instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec) (resource.Quantity, bool) {
+	const totalPercentage = 100
+	instancetypeOverCommit := instancetypeSpec.Memory.OvercommitPercent
+	if instancetypeOverCommit <= 0 {
+		return resource.Quantity{}, false
+	}
+	instancetypeMemory := instancetypeSpec.Memory.Guest.DeepCopy()
+	podRequestedMemory := int64(float32(instancetypeMemory.Value()) * (1 - float32(instancetypeOverCommit)/totalPercentage))
+	return *resource.NewQuantity(podRequestedMemory, instancetypeMemory.Format), true
+}
+
+// hasInstancetypeMemory reports whether the VMI already has exactly the memory and any overcommitted memory request
+// the instancetype would apply
+func hasInstancetypeMemory(instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec, vmiSpec *virtv1.VirtualMachineInstanceSpec) bool {
+	if vmiSpec.Domain.Memory == nil || !equality.Semantic.DeepEqual(vmiSpec.Domain.Memory, newInstancetypeMemory(instancetypeSpec)) {
+		return false
+	}
+	vmiRequest, hasMemoryRequests := vmiSpec.Domain.Resources.Requests[k8sv1.ResourceMemory]
+	podRequestedMemory, overcommitted := overcommitMemoryRequest(instancetypeSpec)
+	if !overcommitted {
+		return !hasMemoryRequests
+	}
+	return hasMemoryRequests && vmiRequest.Cmp(podRequestedMemory) == 0
+}
+
+const (
+	instancetypeMemoryGuestPath       = "instancetype.spec.memory.guest"
+	instancetypeHugepagesPageSizePath = "instancetype.spec.memory.hugepages.pageSize"
+	instancetypeMemoryMaxGuestPath    = "instancetype.spec.memory.maxGuest"
+	hugepagesPageSizeInvalidErrFmt    = "hugepages page size %q provided by the instance type is invalid: %v"
+	hugepagesMemoryMisalignedErrFmt   = "guest memory %s provided by the instance type is not a multiple of the hugepages page size %s"
+	maxGuestLessThanGuestErrFmt       = "maxGuest memory %s provided by the instance type must be greater than or equal to guest memory %s"
+	instancetypeOvercommitPercentPath = "instancetype.spec.memory.overcommitPercent"
+	overcommitPercentOutOfRangeErrFmt = "overcommitPercent %d provided by the instance type must be between 0 and 100"
+	memoryLimitLessThanGuestErrFmt    = "memory limit %s of %s provided by the VMI must be greater than or equal to " +
+		"guest memory %s of instancetype.spec.memory.guest provided by the instance type"
+)
+
+func validateHugepages(instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec) *conflict.Conflict {
+	if instancetypeSpec.Memory.Hugepages == nil {
+		return nil
+	}
+
+	pageSize, err := resource.ParseQuantity(instancetypeSpec.Memory.Hugepages.PageSize)
+	if err != nil {
+		return conflict.NewWithMessage(
+			fmt.Sprintf(hugepagesPageSizeInvalidErrFmt, instancetypeSpec.Memory.Hugepages.PageSize, err),
+			instancetypeHugepagesPageSizePath,
+		)
+	}
+
+	if pageSize.Value() <= 0 {
+		return conflict.NewWithMessage(
+			fmt.Sprintf(hugepagesPageSizeInvalidErrFmt, instancetypeSpec.Memory.Hugepages.PageSize, "must be greater than zero"),
+			instancetypeHugepagesPageSizePath,
+		)
+	}
+
+	if instancetypeSpec.Memory.Guest.Value()%pageSize.Value() != 0 {
+		return conflict.NewWithMessage(
+			fmt.Sprintf(hugepagesMemoryMisalignedErrFmt, quantityString(instancetypeSpec.Memory.Guest), pageSize.String()),
+			instancetypeMemoryGuestPath,
+		)
 	}
 
 	return nil
 }
+
+func validateMaxGuest(instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec) *conflict.Conflict {
+	maxGuest := instancetypeSpec.Memory.MaxGuest
+	if maxGuest == nil || maxGuest.Cmp(instancetypeSpec.Memory.Guest) >= 0 {
+		return nil
+	}
+	return conflict.NewWithMessage(
+		fmt.Sprintf(maxGuestLessThanGuestErrFmt, quantityString(*maxGuest), quantityString(instancetypeSpec.Memory.Guest)),
+		instancetypeMemoryMaxGuestPath,
+	)
+}
+
+// validateMemoryLimit accepts a memory limit provided by the VMI when it can accommodate the guest memory of the instancetype
+func validateMemoryLimit(
+	baseConflict *conflict.Conflict,
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	memoryLimit resource.Quantity,
+) *conflict.Conflict {
+	if memoryLimit.Cmp(instancetypeSpec.Memory.Guest) >= 0 {
+		return nil
+	}
+	limitConflict := baseConflict.NewChild("domain", "resources", "limits", string(k8sv1.ResourceMemory))
+	limitConflict.Message = fmt.Sprintf(memoryLimitLessThanGuestErrFmt,
+		memoryLimit.String(), limitConflict.String(), quantityString(instancetypeSpec.Memory.Guest))
+	return limitConflict
+}
+
+// validateOvercommitPercent guards against instancetypes not validated on admission, such as those stored in older revisions
+func validateOvercommitPercent(instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec) *conflict.Conflict {
+	const maxOvercommitPercent = 100
+	if percent := instancetypeSpec.Memory.OvercommitPercent; percent < 0 || percent > maxOvercommitPercent {
+		return conflict.NewWithMessage(fmt.Sprintf(overcommitPercentOutOfRangeErrFmt, percent), instancetypeOvercommitPercentPath)
+	}
+	return nil
+}
+
+// quantityString formats a copy of the quantity as Quantity.String caches its result within the receiver,
+// which would otherwise mutate an instancetype that may be shared between concurrent calls to ApplyToVMI.
+func quantityString(quantity resource.Quantity) string {
+	return quantity.String()
+}
@@ -1,6 +1,8 @@
 package apply_test
 
 import (
+	"fmt"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -12,6 +14,7 @@ import (
 	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
 
 	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
 	"kubevirt.io/kubevirt/pkg/libvmi"
 )
 
@@ -31,7 +34,7 @@ var _ = Describe("instancetype.Spec.Memory", func() {
 
 		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
 			Memory: v1beta1.MemoryInstancetype{
-				Guest: resource.MustParse("512M"),
+				Guest: resource.MustParse("1Gi"),
 				Hugepages: &virtv1.Hugepages{
 					PageSize: "1Gi",
 				},
@@ -48,6 +51,84 @@ var _ = Describe("instancetype.Spec.Memory", func() {
 		Expect(vmi.Spec.Domain.Memory.MaxGuest.Equal(*instancetypeSpec.Memory.MaxGuest)).To(BeTrue())
 	})
 
+	DescribeTable("should apply hugepages to VMI", func(guest, pageSize string) {
+		instancetypeSpec.Memory.Guest = resource.MustParse(guest)
+		instancetypeSpec.Memory.Hugepages = &virtv1.Hugepages{
+			PageSize: pageSize,
+		}
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.Memory.Guest).To(HaveValue(Equal(instancetypeSpec.Memory.Guest)))
+		Expect(vmi.Spec.Domain.Memory.Hugepages).To(HaveValue(Equal(virtv1.Hugepages{PageSize: pageSize})))
+	},
+		Entry("with 2Mi pages", "512Mi", "2Mi"),
+		Entry("with 1Gi pages", "1Gi", "1Gi"),
+	)
+
+	DescribeTable("should return a conflict when guest memory is not a multiple of the hugepages page size",
+		func(guest, pageSize, expectedField, expectedMessage string) {
+			instancetypeSpec.Memory.Guest = resource.MustParse(guest)
+			instancetypeSpec.Memory.Hugepages = &virtv1.Hugepages{
+				PageSize: pageSize,
+			}
+
+			conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].String()).To(Equal(expectedField))
+			Expect(conflicts[0].Error()).To(Equal(expectedMessage))
+			Expect(vmi.Spec.Domain.Memory).To(BeNil())
+		},
+		Entry("with 2Mi pages", "513Mi", "2Mi", "instancetype.spec.memory.guest",
+			"guest memory 513Mi provided by the instance type is not a multiple of the hugepages page size 2Mi"),
+		Entry("with 1Gi pages", "512M", "1Gi", "instancetype.spec.memory.guest",
+			"guest memory 512M provided by the instance type is not a multiple of the hugepages page size 1Gi"),
+		Entry("with an invalid page size", "1Gi", "foo", "instancetype.spec.memory.hugepages.pageSize",
+			"hugepages page size \"foo\" provided by the instance type is invalid: quantities must match the regular expression "+
+				"'^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'"),
+	)
+
+	It("should detect a hugepages conflict", func() {
+		vmi.Spec.Domain.Memory = &virtv1.Memory{
+			Hugepages: &virtv1.Hugepages{
+				PageSize: "2Mi",
+			},
+		}
+
+		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.memory"))
+	})
+
+	It("should apply maxGuest equal to guest memory", func() {
+		maxGuest := instancetypeSpec.Memory.Guest.DeepCopy()
+		instancetypeSpec.Memory.MaxGuest = &maxGuest
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.Memory.MaxGuest).To(HaveValue(Equal(instancetypeSpec.Memory.Guest)))
+	})
+
+	It("should return a conflict when maxGuest is less than guest memory", func() {
+		lowMaxGuest := resource.MustParse("512Mi")
+		instancetypeSpec.Memory.MaxGuest = &lowMaxGuest
+
+		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].String()).To(Equal("instancetype.spec.memory.maxGuest"))
+		Expect(conflicts[0].Error()).To(Equal(
+			"maxGuest memory 512Mi provided by the instance type must be greater than or equal to guest memory 1Gi"))
+		Expect(vmi.Spec.Domain.Memory).To(BeNil())
+	})
+
+	It("should detect maxGuest conflict", func() {
+		vmiMaxGuest := resource.MustParse("4Gi")
+		vmi.Spec.Domain.Memory = &virtv1.Memory{
+			MaxGuest: &vmiMaxGuest,
+		}
+
+		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.memory.maxGuest"))
+	})
+
 	It("should apply memory overcommit correctly to VMI", func() {
 		instancetypeSpec.Memory.Hugepages = nil
 		instancetypeSpec.Memory.OvercommitPercent = 15
@@ -61,6 +142,78 @@ var _ = Describe("instancetype.Spec.Memory", func() {
 		Expect(memRequest.Value()).To(Equal(expectedOverhead))
 	})
 
+	DescribeTable("should apply memory requests for an overcommit percentage", func(percent int, expectedRequest string) {
+		instancetypeSpec.Memory.Hugepages = nil
+		instancetypeSpec.Memory.MaxGuest = nil
+		instancetypeSpec.Memory.OvercommitPercent = percent
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.Memory.Guest).To(HaveValue(Equal(instancetypeSpec.Memory.Guest)))
+		memRequest := vmi.Spec.Domain.Resources.Requests[k8sv1.ResourceMemory]
+		Expect(memRequest.Cmp(resource.MustParse(expectedRequest))).To(BeZero(), "got %s", memRequest.String())
+	},
+		Entry("of 75", 75, "256Mi"),
+		Entry("of 25", 25, "768Mi"),
+		Entry("of 50", 50, "512Mi"),
+		Entry("of 100", 100, "0"),
+	)
+
+	It("should not apply memory requests without an overcommit percentage", func() {
+		instancetypeSpec.Memory.OvercommitPercent = 0
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.Resources.Requests).ToNot(HaveKey(k8sv1.ResourceMemory))
+	})
+
+	DescribeTable("should return a conflict for an out of range overcommit percentage", func(percent int) {
+		instancetypeSpec.Memory.Hugepages = nil
+		instancetypeSpec.Memory.OvercommitPercent = percent
+
+		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].String()).To(Equal("instancetype.spec.memory.overcommitPercent"))
+		Expect(conflicts[0].Error()).To(Equal(
+			fmt.Sprintf("overcommitPercent %d provided by the instance type must be between 0 and 100", percent)))
+		Expect(vmi.Spec.Domain.Resources.Requests).ToNot(HaveKey(k8sv1.ResourceMemory))
+	},
+		Entry("below 0", -1),
+		Entry("above 100", 101),
+	)
+
+	It("should return a conflict when the VMI already requests memory with an overcommit percentage", func() {
+		instancetypeSpec.Memory.Hugepages = nil
+		instancetypeSpec.Memory.OvercommitPercent = 25
+		vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{
+			k8sv1.ResourceMemory: resource.MustParse("128Mi"),
+		}
+
+		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.resources.requests.memory"))
+	})
+
+	DescribeTable("should accept memory identical to that applied by the instance type", func(overcommitPercent int) {
+		instancetypeSpec.Memory.OvercommitPercent = overcommitPercent
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		expandedSpec := vmi.Spec.DeepCopy()
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec).To(Equal(*expandedSpec))
+	},
+		Entry("without overcommit", 0),
+		Entry("with overcommit", 25),
+	)
+
+	It("should return a conflict when the memory request applied by the instance type has since been changed", func() {
+		instancetypeSpec.Memory.OvercommitPercent = 25
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		vmi.Spec.Domain.Resources.Requests[k8sv1.ResourceMemory] = resource.MustParse("128Mi")
+
+		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.memory.maxGuest"))
+	})
+
 	It("should detect memory conflict", func() {
 		vmiMemGuest := resource.MustParse("512M")
 		vmi.Spec.Domain.Memory = &virtv1.Memory{
@@ -90,7 +243,7 @@ var _ = Describe("instancetype.Spec.Memory", func() {
 		Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.resources.requests.memory"))
 	})
 
-	It("should return a conflict if vmi.Spec.Domain.Resources.Limits[k8sv1.ResourceMemory] already defined", func() {
+	It("should return a conflict if vmi.Spec.Domain.Resources.Limits[k8sv1.ResourceMemory] is less than the guest memory", func() {
 		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
 			Memory: v1beta1.MemoryInstancetype{
 				Guest: resource.MustParse("512M"),
@@ -106,5 +259,74 @@ var _ = Describe("instancetype.Spec.Memory", func() {
 		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
 		Expect(conflicts).To(HaveLen(1))
 		Expect(conflicts[0].String()).To(Equal("spec.template.spec.domain.resources.limits.memory"))
+		Expect(conflicts[0].Error()).To(Equal(
+			"memory limit 128Mi of spec.template.spec.domain.resources.limits.memory provided by the VMI must be greater than or equal to " +
+				"guest memory 512M of instancetype.spec.memory.guest provided by the instance type"))
+		Expect(vmi.Spec.Domain.Memory).To(BeNil())
+	})
+
+	DescribeTable("should apply to VMI with a compatible vmi.Spec.Domain.Resources.Limits[k8sv1.ResourceMemory]", func(limit string) {
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			Memory: v1beta1.MemoryInstancetype{
+				Guest: resource.MustParse("512Mi"),
+			},
+		}
+
+		vmi.Spec.Domain.Resources = virtv1.ResourceRequirements{
+			Limits: k8sv1.ResourceList{
+				k8sv1.ResourceMemory: resource.MustParse(limit),
+			},
+		}
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.Memory.Guest).To(HaveValue(Equal(instancetypeSpec.Memory.Guest)))
+		Expect(vmi.Spec.Domain.Resources.Limits).To(HaveKeyWithValue(k8sv1.ResourceMemory, resource.MustParse(limit)))
+	},
+		Entry("equal to the guest memory", "512Mi"),
+		Entry("greater than the guest memory", "1Gi"),
+	)
+
+	Context("WithNodeAllocatableMemory", func() {
+		var warnings conflict.Conflicts
+
+		BeforeEach(func() {
+			warnings = nil
+		})
+
+		applyWithNodes := func(allocatable ...resource.Quantity) conflict.Conflicts {
+			return apply.NewVMIApplier(
+				apply.WithNodeAllocatableMemory(allocatable...),
+				apply.WithWarningHandler(func(warning *conflict.Conflict) {
+					warnings = append(warnings, warning)
+				}),
+			).ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
+		}
+
+		DescribeTable("should not warn when the guest memory fits a node", func(allocatable ...resource.Quantity) {
+			Expect(applyWithNodes(allocatable...)).To(Succeed())
+			Expect(vmi.Spec.Domain.Memory.Guest).To(HaveValue(Equal(instancetypeSpec.Memory.Guest)))
+			Expect(warnings).To(BeEmpty())
+		},
+			Entry("without any node"),
+			Entry("with a single larger node", resource.MustParse("2Gi")),
+			Entry("with a node of exactly the guest memory", resource.MustParse("1Gi")),
+			Entry("with only one of several nodes large enough", resource.MustParse("512Mi"), resource.MustParse("4Gi")),
+		)
+
+		It("should warn and still apply when the guest memory exceeds every node", func() {
+			Expect(applyWithNodes(resource.MustParse("512Mi"), resource.MustParse("768Mi"))).To(Succeed())
+			Expect(vmi.Spec.Domain.Memory.Guest).To(HaveValue(Equal(instancetypeSpec.Memory.Guest)))
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0].String()).To(Equal("spec.template.spec.domain.memory.guest"))
+			Expect(warnings[0].Error()).To(Equal(
+				"guest memory 1Gi provided by the instance type exceeds the allocatable memory of every schedulable node, the largest being 768Mi"))
+		})
+
+		It("should not warn without node allocatable memory", func() {
+			Expect(apply.NewVMIApplier(apply.WithWarningHandler(func(warning *conflict.Conflict) {
+				warnings = append(warnings, warning)
+			})).ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(warnings).To(BeEmpty())
+		})
 	})
 })
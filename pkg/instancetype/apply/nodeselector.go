@@ -20,6 +20,7 @@ package apply
 
 import (
 	"maps"
+	"slices"
 
 	virtv1 "kubevirt.io/api/core/v1"
 	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
@@ -28,6 +29,7 @@ import (
 )
 
 func applyNodeSelector(
+	opts *applyOptions,
 	baseConflict *conflict.Conflict,
 	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
 	vmiSpec *virtv1.VirtualMachineInstanceSpec,
@@ -36,11 +38,35 @@ func applyNodeSelector(
 		return nil
 	}
 
-	if vmiSpec.NodeSelector != nil {
-		return conflict.Conflicts{baseConflict.NewChild("nodeSelector")}
+	if vmiSpec.NodeSelector == nil {
+		vmiSpec.NodeSelector = maps.Clone(instancetypeSpec.NodeSelector)
+		return nil
 	}
 
-	vmiSpec.NodeSelector = maps.Clone(instancetypeSpec.NodeSelector)
+	return mergeNodeSelector(opts, baseConflict, instancetypeSpec, vmiSpec)
+}
+
+// mergeNodeSelector adds each instancetype key not already present within the nodeSelector of the VMI, leaving
+// existing keys untouched. Keys with identical values are accepted while those with differing values are reported
+// as conflicts, so applying to an already expanded VMI is a no-op.
+func mergeNodeSelector(
+	opts *applyOptions,
+	baseConflict *conflict.Conflict,
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+) conflict.Conflicts {
+	var conflicts conflict.Conflicts
+	for _, key := range slices.Sorted(maps.Keys(instancetypeSpec.NodeSelector)) {
+		value := instancetypeSpec.NodeSelector[key]
+		vmiValue, exists := vmiSpec.NodeSelector[key]
+		if !exists {
+			vmiSpec.NodeSelector[key] = value
+			continue
+		}
+		if vmiValue != value {
+			conflicts = append(conflicts, conflict.NewFromPath(baseConflict.Child("nodeSelector").Key(key)))
+		}
+	}
 
-	return nil
+	return opts.resolveConflicts(conflicts...)
 }
@@ -46,14 +46,67 @@ var _ = Describe("instancetype.spec.NodeSelector", func() {
 		Expect(vmi.Spec.NodeSelector).To(Equal(map[string]string{"key": "value"}))
 	})
 
-	It("should return a conflict if vmi.Spec.NodeSelector is already set and instancetype.NodeSelector is defined", func() {
+	It("should keep unrelated keys of the VMI and add those of the instancetype", func() {
 		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
-			NodeSelector: map[string]string{"key": "value"},
+			NodeSelector: map[string]string{"instancetype": "value"},
 		}
-		vmi.Spec.NodeSelector = map[string]string{"key": "value"}
+		vmi.Spec.NodeSelector = map[string]string{"vmi": "value", "another-vmi": "value"}
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.NodeSelector).To(Equal(map[string]string{"vmi": "value", "another-vmi": "value", "instancetype": "value"}))
+	})
+
+	DescribeTable("should merge", func(vmiNodeSelector, instancetypeNodeSelector, expectedNodeSelector map[string]string) {
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			NodeSelector: instancetypeNodeSelector,
+		}
+		vmi.Spec.NodeSelector = vmiNodeSelector
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.NodeSelector).To(Equal(expectedNodeSelector))
+	},
+		Entry("disjoint keys",
+			map[string]string{"vmi": "value"},
+			map[string]string{"instancetype": "value"},
+			map[string]string{"vmi": "value", "instancetype": "value"},
+		),
+		Entry("identical overlapping keys",
+			map[string]string{"key": "value", "vmi": "value"},
+			map[string]string{"key": "value", "instancetype": "value"},
+			map[string]string{"key": "value", "vmi": "value", "instancetype": "value"},
+		),
+		Entry("a nodeSelector identical to that of the instancetype",
+			map[string]string{"key": "value"},
+			map[string]string{"key": "value"},
+			map[string]string{"key": "value"},
+		),
+		Entry("into an empty nodeSelector",
+			map[string]string{},
+			map[string]string{"instancetype": "value"},
+			map[string]string{"instancetype": "value"},
+		),
+	)
+
+	It("should return a conflict for each divergent overlapping key", func() {
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			NodeSelector: map[string]string{"b": "instancetype", "a": "instancetype", "key": "value"},
+		}
+		vmi.Spec.NodeSelector = map[string]string{"a": "vmi", "b": "vmi", "key": "value", "vmi": "value"}
 
 		conflicts := vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)
-		Expect(conflicts).To(HaveLen(1))
-		Expect(conflicts[0].String()).To(Equal("spec.template.spec.nodeSelector"))
+		Expect(conflicts).To(HaveLen(2))
+		Expect(conflicts[0].Path.String()).To(Equal("spec.template.spec.nodeSelector[a]"))
+		Expect(conflicts[1].Path.String()).To(Equal("spec.template.spec.nodeSelector[b]"))
+		Expect(vmi.Spec.NodeSelector).To(Equal(map[string]string{"a": "vmi", "b": "vmi", "key": "value", "vmi": "value"}))
+	})
+
+	It("should not mutate the nodeSelector of the instancetype", func() {
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			NodeSelector: map[string]string{"instancetype": "value"},
+		}
+		vmi.Spec.NodeSelector = map[string]string{"vmi": "value"}
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(instancetypeSpec.NodeSelector).To(Equal(map[string]string{"instancetype": "value"}))
 	})
 })
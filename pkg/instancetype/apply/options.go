@@ -0,0 +1,207 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+package apply
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+)
+
+type Option func(*vmiApplier)
+
+// WithVMIOverrides allows values already provided by the VMI to take precedence over those of the instancetype.
+// Instead of returning a conflict the VMI value is kept and a warning is emitted to any registered warning handler.
+func WithVMIOverrides() Option {
+	return func(a *vmiApplier) {
+		a.options.vmiOverrides = true
+	}
+}
+
+// WithWarningHandler registers a handler called with each warning emitted while applying an instancetype.
+func WithWarningHandler(handler func(warning *conflict.Conflict)) Option {
+	return func(a *vmiApplier) {
+		a.options.warningHandler = handler
+	}
+}
+
+// WithGPUMerge appends instancetype GPUs to those already provided by the VMI instead of returning a conflict.
+// GPUs are matched by name with a conflict only returned when both define the same GPU differently.
+func WithGPUMerge() Option {
+	return func(a *vmiApplier) {
+		a.options.mergeGPUs = true
+	}
+}
+
+// WithHostDeviceMerge appends instancetype host devices to those already provided by the VMI instead of returning a conflict.
+// Host devices are matched by name with a conflict only returned when both define the same host device differently.
+func WithHostDeviceMerge() Option {
+	return func(a *vmiApplier) {
+		a.options.mergeHostDevices = true
+	}
+}
+
+// AnnotationPolicy controls how instancetype annotations and labels colliding with those of the VMI are handled
+type AnnotationPolicy string
+
+const (
+	// AnnotationPolicyConflict returns a conflict for each colliding annotation and is the default policy
+	AnnotationPolicyConflict AnnotationPolicy = "Conflict"
+	// AnnotationPolicyPreserve keeps the VMI annotation and emits a warning for each colliding annotation
+	AnnotationPolicyPreserve AnnotationPolicy = "Preserve"
+	// AnnotationPolicyOverwrite replaces the VMI annotation with that of the instancetype
+	AnnotationPolicyOverwrite AnnotationPolicy = "Overwrite"
+)
+
+// WithAnnotationPolicy selects how instancetype annotations and labels colliding with those of the VMI are handled.
+func WithAnnotationPolicy(policy AnnotationPolicy) Option {
+	return func(a *vmiApplier) {
+		a.options.annotationPolicy = policy
+	}
+}
+
+// WithCollisionHandler registers a handler called with each instancetype annotation or label colliding with a
+// different value already set on the VMI. The handler is called regardless of the AnnotationPolicy of the applier,
+// with a conflict describing both values, to help explain unexpected values on the VMI.
+func WithCollisionHandler(handler func(collision *conflict.Conflict)) Option {
+	return func(a *vmiApplier) {
+		a.options.collisionHandler = handler
+	}
+}
+
+// WithMetadataTemplates resolves annotation and label values of the instancetype containing template actions from the
+// metadata of owner, typically the VM the VMI belongs to, with the {{.Name}} and {{.Namespace}} tokens available.
+// Values are applied literally without this option. Invalid templates and tokens resolving to an empty value are
+// returned as conflicts.
+func WithMetadataTemplates(owner metav1.Object) Option {
+	return func(a *vmiApplier) {
+		a.options.metadataTemplate = &metadataTemplateData{
+			name:      owner.GetName(),
+			namespace: owner.GetNamespace(),
+		}
+	}
+}
+
+// WithEventSink registers a sink called with each field of the VMI mutated by the instancetype or preference.
+// Mutations are only recorded once the instancetype or preference has been applied without conflicts.
+func WithEventSink(sink EventSink) Option {
+	return func(a *vmiApplier) {
+		a.options.eventSink = sink
+	}
+}
+
+// WithNodeAllocatableMemory provides the allocatable memory of each schedulable node, emitting a warning to any registered
+// warning handler when the guest memory applied from the instancetype exceeds that of every node. The instancetype is
+// still applied as the nodes of the cluster may change before the VMI is scheduled.
+func WithNodeAllocatableMemory(allocatable ...resource.Quantity) Option {
+	return func(a *vmiApplier) {
+		a.options.nodeAllocatableMemory = allocatable
+	}
+}
+
+// FieldCategory groups the fields of an instancetype that are applied together
+type FieldCategory string
+
+const (
+	// FieldCategoryCPU covers the guest CPU topology and all other CPU settings
+	FieldCategoryCPU FieldCategory = "cpu"
+	// FieldCategoryMemory covers the guest memory, hugepages and memory overcommit
+	FieldCategoryMemory FieldCategory = "memory"
+	// FieldCategoryGPUs covers GPUs
+	FieldCategoryGPUs FieldCategory = "gpus"
+	// FieldCategoryHostDevices covers host devices
+	FieldCategoryHostDevices FieldCategory = "hostDevices"
+	// FieldCategoryScheduling covers the node selector and scheduler name
+	FieldCategoryScheduling FieldCategory = "scheduling"
+	// FieldCategoryIOThreadsPolicy covers the IOThreads policy
+	FieldCategoryIOThreadsPolicy FieldCategory = "ioThreadsPolicy"
+	// FieldCategoryLaunchSecurity covers launch security
+	FieldCategoryLaunchSecurity FieldCategory = "launchSecurity"
+	// FieldCategoryAnnotations covers the annotations and labels of the instancetype
+	FieldCategoryAnnotations FieldCategory = "annotations"
+)
+
+// WithFieldCategories restricts the fields applied from the instancetype to those of the provided categories,
+// leaving the fields of all other categories untouched on the VMI. Every category is applied when none are provided.
+// The preference is applied as usual.
+func WithFieldCategories(categories ...FieldCategory) Option {
+	return func(a *vmiApplier) {
+		a.options.fieldCategories = nil
+		if len(categories) == 0 {
+			return
+		}
+		a.options.fieldCategories = make(map[FieldCategory]struct{}, len(categories))
+		for _, category := range categories {
+			a.options.fieldCategories[category] = struct{}{}
+		}
+	}
+}
+
+// applyOptions is passed to each applyX function and controls how conflicts are handled
+type applyOptions struct {
+	vmiOverrides     bool
+	mergeGPUs        bool
+	mergeHostDevices bool
+	annotationPolicy AnnotationPolicy
+	warningHandler   func(warning *conflict.Conflict)
+	collisionHandler func(collision *conflict.Conflict)
+	eventSink        EventSink
+	fieldCategories  map[FieldCategory]struct{}
+	metadataTemplate *metadataTemplateData
+	// nodeAllocatableMemory is only checked against the guest memory when provided
+	nodeAllocatableMemory []resource.Quantity
+	// instancetypeSource and preferenceSource are only provided by ApplyToVMIWithResult, naming the sources of events
+	// and providing the labels of the instancetype
+	instancetypeSource *AppliedSource
+	preferenceSource   *AppliedSource
+}
+
+// resolveConflicts returns the provided conflicts unless VMI overrides are enabled,
+// in which case each conflict is instead emitted as a warning and nothing is returned.
+func (o *applyOptions) resolveConflicts(conflicts ...*conflict.Conflict) conflict.Conflicts {
+	if !o.vmiOverrides {
+		return conflicts
+	}
+	for _, c := range conflicts {
+		o.warn(c)
+	}
+	return nil
+}
+
+func (o *applyOptions) warn(warning *conflict.Conflict) {
+	if o.warningHandler != nil {
+		o.warningHandler(warning)
+	}
+}
+
+func (o *applyOptions) reportCollision(collision *conflict.Conflict) {
+	if o.collisionHandler != nil {
+		o.collisionHandler(collision)
+	}
+}
+
+// appliesCategory returns true when the fields of the category should be applied from the instancetype
+func (o *applyOptions) appliesCategory(category FieldCategory) bool {
+	if o.fieldCategories == nil {
+		return true
+	}
+	_, applies := o.fieldCategories[category]
+	return applies
+}
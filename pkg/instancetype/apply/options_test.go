@@ -0,0 +1,228 @@
+package apply_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+	"kubevirt.io/kubevirt/pkg/libvmi"
+	"kubevirt.io/kubevirt/pkg/pointer"
+)
+
+var _ = Describe("VMIApplier options", func() {
+	var (
+		vmi              *virtv1.VirtualMachineInstance
+		instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec
+
+		field = k8sfield.NewPath("spec", "template", "spec")
+	)
+
+	BeforeEach(func() {
+		vmi = libvmi.New()
+
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			SchedulerName: "instancetype-scheduler",
+			CPU: v1beta1.CPUInstancetype{
+				Guest: uint32(2),
+				Model: pointer.P("host-passthrough"),
+			},
+			Memory: v1beta1.MemoryInstancetype{
+				Guest: resource.MustParse("512M"),
+			},
+		}
+	})
+
+	Context("WithVMIOverrides", func() {
+		var warnings conflict.Conflicts
+
+		BeforeEach(func() {
+			warnings = nil
+
+			vmiMemGuest := resource.MustParse("1Gi")
+			vmi.Spec.SchedulerName = "vmi-scheduler"
+			vmi.Spec.Domain.CPU = &virtv1.CPU{
+				Sockets: 4,
+			}
+			vmi.Spec.Domain.Memory = &virtv1.Memory{
+				Guest: &vmiMemGuest,
+			}
+		})
+
+		It("should return conflicts by default", func() {
+			conflicts := apply.NewVMIApplier().ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(conflicts).To(ConsistOf(conflict.Conflicts{
+				conflict.New("spec", "template", "spec", "schedulerName"),
+				conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
+				conflict.New("spec", "template", "spec", "domain", "memory"),
+			}.WithSource(conflict.SourceInstancetype)))
+		})
+
+		It("should keep VMI values and record warnings instead of conflicts", func() {
+			vmiApplier := apply.NewVMIApplier(
+				apply.WithVMIOverrides(),
+				apply.WithWarningHandler(func(warning *conflict.Conflict) {
+					warnings = append(warnings, warning)
+				}),
+			)
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+			Expect(warnings).To(ConsistOf(
+				conflict.New("spec", "template", "spec", "schedulerName"),
+				conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
+				conflict.New("spec", "template", "spec", "domain", "memory"),
+			))
+
+			Expect(vmi.Spec.SchedulerName).To(Equal("vmi-scheduler"))
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(uint32(4)))
+			Expect(vmi.Spec.Domain.CPU.Cores).To(BeZero())
+			Expect(vmi.Spec.Domain.CPU.Threads).To(BeZero())
+			Expect(vmi.Spec.Domain.Memory.Guest.String()).To(Equal("1Gi"))
+
+			// Fields not provided by the VMI are still applied from the instancetype
+			Expect(vmi.Spec.Domain.CPU.Model).To(Equal(*instancetypeSpec.CPU.Model))
+		})
+
+		It("should not require a warning handler", func() {
+			vmiApplier := apply.NewVMIApplier(apply.WithVMIOverrides())
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.SchedulerName).To(Equal("vmi-scheduler"))
+		})
+	})
+
+	Context("WithFieldCategories", func() {
+		var (
+			vmiGPUs        []virtv1.GPU
+			vmiHostDevices []virtv1.HostDevice
+		)
+
+		BeforeEach(func() {
+			instancetypeSpec.NodeSelector = map[string]string{"node": "instancetype"}
+			instancetypeSpec.Annotations = map[string]string{"annotation": "instancetype"}
+			instancetypeSpec.GPUs = []virtv1.GPU{{Name: "instancetype-gpu", DeviceName: "vendor.com/instancetype_gpu"}}
+			instancetypeSpec.HostDevices = []virtv1.HostDevice{{Name: "instancetype-hostdevice", DeviceName: "vendor.com/instancetype_hostdevice"}}
+
+			vmiGPUs = []virtv1.GPU{{Name: "vmi-gpu", DeviceName: "vendor.com/vmi_gpu"}}
+			vmiHostDevices = []virtv1.HostDevice{{Name: "vmi-hostdevice", DeviceName: "vendor.com/vmi_hostdevice"}}
+			vmi.Spec.Domain.Devices.GPUs = vmiGPUs
+			vmi.Spec.Domain.Devices.HostDevices = vmiHostDevices
+		})
+
+		It("should only apply the fields of the provided categories", func() {
+			vmiApplier := apply.NewVMIApplier(apply.WithFieldCategories(apply.FieldCategoryCPU, apply.FieldCategoryMemory))
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(instancetypeSpec.CPU.Guest))
+			Expect(vmi.Spec.Domain.CPU.Model).To(Equal(*instancetypeSpec.CPU.Model))
+			Expect(vmi.Spec.Domain.Memory.Guest).To(HaveValue(Equal(instancetypeSpec.Memory.Guest)))
+
+			Expect(vmi.Spec.Domain.Devices.GPUs).To(Equal(vmiGPUs))
+			Expect(vmi.Spec.Domain.Devices.HostDevices).To(Equal(vmiHostDevices))
+			Expect(vmi.Spec.SchedulerName).To(BeEmpty())
+			Expect(vmi.Spec.NodeSelector).To(BeEmpty())
+			Expect(vmi.Annotations).ToNot(HaveKey("annotation"))
+		})
+
+		It("should not report conflicts for fields outside of the provided categories", func() {
+			vmi.Spec.SchedulerName = "vmi-scheduler"
+
+			vmiApplier := apply.NewVMIApplier(apply.WithFieldCategories(apply.FieldCategoryCPU, apply.FieldCategoryMemory))
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.SchedulerName).To(Equal("vmi-scheduler"))
+		})
+
+		It("should apply every category when none are provided", func() {
+			vmi.Spec.Domain.Devices.GPUs = nil
+			vmi.Spec.Domain.Devices.HostDevices = nil
+
+			vmiApplier := apply.NewVMIApplier(apply.WithFieldCategories())
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(instancetypeSpec.CPU.Guest))
+			Expect(vmi.Spec.Domain.Devices.GPUs).To(Equal(instancetypeSpec.GPUs))
+			Expect(vmi.Spec.Domain.Devices.HostDevices).To(Equal(instancetypeSpec.HostDevices))
+			Expect(vmi.Spec.SchedulerName).To(Equal(instancetypeSpec.SchedulerName))
+			Expect(vmi.Spec.NodeSelector).To(Equal(instancetypeSpec.NodeSelector))
+			Expect(vmi.Annotations).To(HaveKeyWithValue("annotation", "instancetype"))
+		})
+	})
+
+	Context("composing options", func() {
+		It("should behave as without options when none are provided", func() {
+			vmiCopy := vmi.DeepCopy()
+
+			var noOptions []apply.Option
+			Expect(apply.NewVMIApplier(noOptions...).ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(apply.NewVMIApplier().ApplyToVMI(field, instancetypeSpec, nil, &vmiCopy.Spec, &vmiCopy.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec).To(Equal(vmiCopy.Spec))
+		})
+
+		DescribeTable("should record the same warnings regardless of the order options are provided in",
+			func(optionsInOrder func(handler func(*conflict.Conflict)) []apply.Option) {
+				vmi.Spec.SchedulerName = "vmi-scheduler"
+
+				var warnings conflict.Conflicts
+				vmiApplier := apply.NewVMIApplier(optionsInOrder(func(warning *conflict.Conflict) {
+					warnings = append(warnings, warning)
+				})...)
+				Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+				Expect(warnings).To(ConsistOf(conflict.New("spec", "template", "spec", "schedulerName")))
+				Expect(vmi.Spec.SchedulerName).To(Equal("vmi-scheduler"))
+			},
+			Entry("with overrides first", func(handler func(*conflict.Conflict)) []apply.Option {
+				return []apply.Option{apply.WithVMIOverrides(), apply.WithWarningHandler(handler)}
+			}),
+			Entry("with the warning handler first", func(handler func(*conflict.Conflict)) []apply.Option {
+				return []apply.Option{apply.WithWarningHandler(handler), apply.WithVMIOverrides()}
+			}),
+		)
+
+		It("should use the last value provided for a repeated option", func() {
+			vmi.Annotations = map[string]string{"annotation": "vmi"}
+			instancetypeSpec.Annotations = map[string]string{"annotation": "instancetype"}
+
+			vmiApplier := apply.NewVMIApplier(
+				apply.WithAnnotationPolicy(apply.AnnotationPolicyPreserve),
+				apply.WithAnnotationPolicy(apply.AnnotationPolicyOverwrite),
+			)
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, nil, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Annotations).To(HaveKeyWithValue("annotation", "instancetype"))
+		})
+
+		It("should apply each of several independent options", func() {
+			vmi.Spec.SchedulerName = "vmi-scheduler"
+			vmi.Annotations = map[string]string{"annotation": "vmi"}
+			vmi.Spec.Domain.Devices.GPUs = []virtv1.GPU{{Name: "vmi-gpu", DeviceName: "vendor.com/vmi_gpu"}}
+			instancetypeSpec.Annotations = map[string]string{"annotation": "instancetype"}
+			instancetypeSpec.GPUs = []virtv1.GPU{{Name: "instancetype-gpu", DeviceName: "vendor.com/instancetype_gpu"}}
+
+			var warnings conflict.Conflicts
+			vmiApplier := apply.NewVMIApplier(
+				apply.WithVMIOverrides(),
+				apply.WithWarningHandler(func(warning *conflict.Conflict) {
+					warnings = append(warnings, warning)
+				}),
+				apply.WithGPUMerge(),
+				apply.WithAnnotationPolicy(apply.AnnotationPolicyOverwrite),
+			)
+			instancetypeSource := &apply.AppliedSource{Labels: map[string]string{"label": "instancetype"}}
+			result := vmiApplier.ApplyToVMIWithResult(field, instancetypeSource, instancetypeSpec, nil, nil, &vmi.Spec, &vmi.ObjectMeta)
+			Expect(result.Conflicts).To(BeEmpty())
+
+			Expect(warnings).To(ConsistOf(conflict.New("spec", "template", "spec", "schedulerName")))
+			Expect(vmi.Spec.SchedulerName).To(Equal("vmi-scheduler"))
+			Expect(vmi.Spec.Domain.Devices.GPUs).To(ConsistOf(
+				virtv1.GPU{Name: "vmi-gpu", DeviceName: "vendor.com/vmi_gpu"},
+				virtv1.GPU{Name: "instancetype-gpu", DeviceName: "vendor.com/instancetype_gpu"},
+			))
+			Expect(vmi.Annotations).To(HaveKeyWithValue("annotation", "instancetype"))
+			Expect(vmi.Labels).To(HaveKeyWithValue("label", "instancetype"))
+		})
+	})
+})
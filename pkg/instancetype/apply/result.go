@@ -0,0 +1,151 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+package apply
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	api "kubevirt.io/api/instancetype"
+	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+)
+
+const (
+	// InstancetypeRevisionAnnotation is the name of the ControllerRevision of an applied instancetype
+	InstancetypeRevisionAnnotation = "kubevirt.io/instancetype-revision-name"
+	// PreferenceRevisionAnnotation is the name of the ControllerRevision of an applied preference
+	PreferenceRevisionAnnotation = "kubevirt.io/preference-revision-name"
+)
+
+// AppliedSource records the kind, name and revision of an instancetype or preference applied to a VMI.
+// Labels, typically taken from the metadata of an instancetype, are applied to the VMI along with its spec.
+type AppliedSource struct {
+	Kind         string
+	Name         string
+	RevisionName string
+	Labels       map[string]string
+}
+
+// NewAppliedSource returns the kind, name and revision of the instancetype or preference referenced by a matcher
+func NewAppliedSource(matcher virtv1.Matcher) *AppliedSource {
+	return &AppliedSource{
+		Kind:         matcher.GetKind(),
+		Name:         matcher.GetName(),
+		RevisionName: matcher.GetRevisionName(),
+	}
+}
+
+// AppliedSourcesFor returns the instancetype and preference referenced by the VM along with the ControllerRevisions they
+// are resolved from, preferring any revision provided by the matchers over those captured within the status of the VM
+func AppliedSourcesFor(vm *virtv1.VirtualMachine) (instancetypeSource, preferenceSource *AppliedSource) {
+	if vm.Spec.Instancetype != nil {
+		instancetypeSource = newAppliedSourceWithStatus(vm.Spec.Instancetype, vm.Status.InstancetypeRef)
+	}
+	if vm.Spec.Preference != nil {
+		preferenceSource = newAppliedSourceWithStatus(vm.Spec.Preference, vm.Status.PreferenceRef)
+	}
+	return instancetypeSource, preferenceSource
+}
+
+func newAppliedSourceWithStatus(matcher virtv1.Matcher, statusRef *virtv1.InstancetypeStatusRef) *AppliedSource {
+	source := NewAppliedSource(matcher)
+	if source.RevisionName == "" && statusRef != nil && statusRef.ControllerRevisionRef != nil {
+		source.RevisionName = statusRef.ControllerRevisionRef.Name
+	}
+	return source
+}
+
+// ApplyResult records the instancetype and preference applied to a VMI along with any conflicts and warnings.
+// Conflicts prevent the VMI from being created while warnings are non-fatal observations, such as VMI values kept
+// over those of the instancetype when VMI overrides are enabled, that callers may surface without rejecting the VMI.
+type ApplyResult struct {
+	Instancetype *AppliedSource
+	Preference   *AppliedSource
+	Conflicts    conflict.Conflicts
+	Warnings     conflict.Conflicts
+}
+
+// ApplyToVMIWithResult behaves exactly as ApplyToVMI while also applying the labels of the instancetype source and
+// recording the source of the instancetype and preference applied and the warnings emitted, which are still passed to
+// any registered warning handler. A source is only recorded when the corresponding spec is provided and no conflicts
+// are found.
+func (a *vmiApplier) ApplyToVMIWithResult(
+	field *k8sfield.Path,
+	instancetypeSource *AppliedSource,
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	preferenceSource *AppliedSource,
+	preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+	vmiMetadata *metav1.ObjectMeta,
+) ApplyResult {
+	// Collect warnings using a copy of the options so that the applier remains safe for concurrent use
+	var result ApplyResult
+	opts := a.options
+	opts.warningHandler = func(warning *conflict.Conflict) {
+		result.Warnings = append(result.Warnings, warning)
+		a.options.warn(warning)
+	}
+	opts.instancetypeSource = instancetypeSource
+	opts.preferenceSource = preferenceSource
+	result.Conflicts = a.apply(&opts, field, instancetypeSpec, preferenceSpec, vmiSpec, vmiMetadata)
+	if len(result.Conflicts) > 0 {
+		return result
+	}
+	if instancetypeSpec != nil {
+		result.Instancetype = instancetypeSource
+	}
+	if preferenceSpec != nil {
+		result.Preference = preferenceSource
+	}
+	return result
+}
+
+// Annotations returns the name and revision of each applied source as annotations suitable for the metadata of the VMI
+func (r ApplyResult) Annotations() map[string]string {
+	annotations := map[string]string{}
+	if r.Instancetype != nil {
+		nameAnnotation := virtv1.ClusterInstancetypeAnnotation
+		switch strings.ToLower(r.Instancetype.Kind) {
+		case api.PluralResourceName, api.SingularResourceName:
+			nameAnnotation = virtv1.InstancetypeAnnotation
+		}
+		addSourceAnnotations(annotations, r.Instancetype, nameAnnotation, InstancetypeRevisionAnnotation)
+	}
+	if r.Preference != nil {
+		nameAnnotation := virtv1.ClusterPreferenceAnnotation
+		switch strings.ToLower(r.Preference.Kind) {
+		case api.PluralPreferenceResourceName, api.SingularPreferenceResourceName:
+			nameAnnotation = virtv1.PreferenceAnnotation
+		}
+		addSourceAnnotations(annotations, r.Preference, nameAnnotation, PreferenceRevisionAnnotation)
+	}
+	return annotations
+}
+
+func addSourceAnnotations(annotations map[string]string, source *AppliedSource, nameAnnotation, revisionAnnotation string) {
+	annotations[nameAnnotation] = source.Name
+	if source.RevisionName != "" {
+		annotations[revisionAnnotation] = source.RevisionName
+	}
+}
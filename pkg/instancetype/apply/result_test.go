@@ -0,0 +1,234 @@
+package apply_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	instancetypeapi "kubevirt.io/api/instancetype"
+	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+	"kubevirt.io/kubevirt/pkg/libvmi"
+)
+
+var _ = Describe("ApplyToVMIWithResult", func() {
+	const (
+		instancetypeName         = "instancetype"
+		instancetypeRevisionName = "instancetype-revision"
+		preferenceName           = "preference"
+		preferenceRevisionName   = "preference-revision"
+	)
+
+	var (
+		vm               *virtv1.VirtualMachine
+		instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec
+		preferenceSpec   *v1beta1.VirtualMachinePreferenceSpec
+
+		vmiApplier = apply.NewVMIApplier()
+		field      = k8sfield.NewPath("spec", "template", "spec")
+	)
+
+	BeforeEach(func() {
+		vm = libvmi.NewVirtualMachine(libvmi.New())
+		vm.Spec.Instancetype = &virtv1.InstancetypeMatcher{
+			Kind:         instancetypeapi.SingularResourceName,
+			Name:         instancetypeName,
+			RevisionName: instancetypeRevisionName,
+		}
+		vm.Spec.Preference = &virtv1.PreferenceMatcher{
+			Kind:         instancetypeapi.SingularPreferenceResourceName,
+			Name:         preferenceName,
+			RevisionName: preferenceRevisionName,
+		}
+
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			CPU: v1beta1.CPUInstancetype{
+				Guest: uint32(2),
+			},
+			Memory: v1beta1.MemoryInstancetype{
+				Guest: resource.MustParse("512Mi"),
+			},
+		}
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			Machine: &v1beta1.MachinePreferences{
+				PreferredMachineType: "q35",
+			},
+		}
+	})
+
+	applyToVM := func() apply.ApplyResult {
+		return vmiApplier.ApplyToVMIWithResult(
+			field,
+			apply.NewAppliedSource(vm.Spec.Instancetype), instancetypeSpec,
+			apply.NewAppliedSource(vm.Spec.Preference), preferenceSpec,
+			&vm.Spec.Template.Spec, &vm.Spec.Template.ObjectMeta,
+		)
+	}
+
+	It("should record the applied instancetype and preference", func() {
+		result := applyToVM()
+		Expect(result.Conflicts).To(BeEmpty())
+		Expect(result.Instancetype).To(HaveValue(Equal(apply.AppliedSource{
+			Kind:         instancetypeapi.SingularResourceName,
+			Name:         instancetypeName,
+			RevisionName: instancetypeRevisionName,
+		})))
+		Expect(result.Preference).To(HaveValue(Equal(apply.AppliedSource{
+			Kind:         instancetypeapi.SingularPreferenceResourceName,
+			Name:         preferenceName,
+			RevisionName: preferenceRevisionName,
+		})))
+		Expect(vm.Spec.Template.Spec.Domain.Machine.Type).To(Equal("q35"))
+	})
+
+	It("should only record sources with a spec provided", func() {
+		preferenceSpec = nil
+
+		result := applyToVM()
+		Expect(result.Conflicts).To(BeEmpty())
+		Expect(result.Instancetype).ToNot(BeNil())
+		Expect(result.Preference).To(BeNil())
+	})
+
+	It("should not record any sources when conflicts are found", func() {
+		vm.Spec.Template.Spec.Domain.CPU = &virtv1.CPU{
+			Sockets: 4,
+		}
+
+		result := applyToVM()
+		Expect(result.Conflicts).To(Equal(conflict.Conflicts{
+			conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
+		}.WithSource(conflict.SourceInstancetype)))
+		Expect(result.Instancetype).To(BeNil())
+		Expect(result.Preference).To(BeNil())
+		Expect(result.Annotations()).To(BeEmpty())
+	})
+
+	Context("with warnings", func() {
+		BeforeEach(func() {
+			vm.Spec.Template.Spec.Domain.CPU = &virtv1.CPU{
+				Sockets: 4,
+			}
+		})
+
+		It("should return warnings without conflicts", func() {
+			var handled conflict.Conflicts
+			vmiApplier := apply.NewVMIApplier(
+				apply.WithVMIOverrides(),
+				apply.WithWarningHandler(func(warning *conflict.Conflict) {
+					handled = append(handled, warning)
+				}),
+			)
+
+			result := vmiApplier.ApplyToVMIWithResult(
+				field,
+				apply.NewAppliedSource(vm.Spec.Instancetype), instancetypeSpec,
+				apply.NewAppliedSource(vm.Spec.Preference), preferenceSpec,
+				&vm.Spec.Template.Spec, &vm.Spec.Template.ObjectMeta,
+			)
+			Expect(result.Conflicts).To(BeEmpty())
+			Expect(result.Warnings).To(Equal(conflict.Conflicts{
+				conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
+			}))
+			Expect(result.Instancetype).ToNot(BeNil())
+			Expect(result.Preference).ToNot(BeNil())
+			Expect(handled).To(Equal(result.Warnings))
+		})
+
+		It("should not return warnings with conflicts", func() {
+			result := applyToVM()
+			Expect(result.Conflicts).To(HaveLen(1))
+			Expect(result.Warnings).To(BeEmpty())
+		})
+	})
+
+	DescribeTable("should provide annotations", func(result apply.ApplyResult, expectedAnnotations map[string]string) {
+		Expect(result.Annotations()).To(Equal(expectedAnnotations))
+	},
+		Entry("for namespaced sources",
+			apply.ApplyResult{
+				Instancetype: &apply.AppliedSource{Kind: "VirtualMachineInstancetype", Name: "it", RevisionName: "it-rev"},
+				Preference:   &apply.AppliedSource{Kind: "VirtualMachinePreference", Name: "pref", RevisionName: "pref-rev"},
+			},
+			map[string]string{
+				virtv1.InstancetypeAnnotation:        "it",
+				apply.InstancetypeRevisionAnnotation: "it-rev",
+				virtv1.PreferenceAnnotation:          "pref",
+				apply.PreferenceRevisionAnnotation:   "pref-rev",
+			},
+		),
+		Entry("for cluster wide sources",
+			apply.ApplyResult{
+				Instancetype: &apply.AppliedSource{Kind: "VirtualMachineClusterInstancetype", Name: "it", RevisionName: "it-rev"},
+				Preference:   &apply.AppliedSource{Kind: "VirtualMachineClusterPreference", Name: "pref", RevisionName: "pref-rev"},
+			},
+			map[string]string{
+				virtv1.ClusterInstancetypeAnnotation: "it",
+				apply.InstancetypeRevisionAnnotation: "it-rev",
+				virtv1.ClusterPreferenceAnnotation:   "pref",
+				apply.PreferenceRevisionAnnotation:   "pref-rev",
+			},
+		),
+		Entry("for sources without a kind or revision",
+			apply.ApplyResult{
+				Instancetype: &apply.AppliedSource{Name: "it"},
+				Preference:   &apply.AppliedSource{Name: "pref"},
+			},
+			map[string]string{
+				virtv1.ClusterInstancetypeAnnotation: "it",
+				virtv1.ClusterPreferenceAnnotation:   "pref",
+			},
+		),
+	)
+})
+
+var _ = Describe("AppliedSourcesFor", func() {
+	var vm *virtv1.VirtualMachine
+
+	BeforeEach(func() {
+		vm = libvmi.NewVirtualMachine(libvmi.New())
+	})
+
+	It("should not return sources for a VM without matchers", func() {
+		instancetypeSource, preferenceSource := apply.AppliedSourcesFor(vm)
+		Expect(instancetypeSource).To(BeNil())
+		Expect(preferenceSource).To(BeNil())
+	})
+
+	It("should prefer the revisions of the matchers over those of the status", func() {
+		vm.Spec.Instancetype = &virtv1.InstancetypeMatcher{Name: "it", RevisionName: "it-matcher-rev"}
+		vm.Spec.Preference = &virtv1.PreferenceMatcher{Name: "pref", RevisionName: "pref-matcher-rev"}
+		vm.Status.InstancetypeRef = &virtv1.InstancetypeStatusRef{
+			ControllerRevisionRef: &virtv1.ControllerRevisionRef{Name: "it-status-rev"},
+		}
+		vm.Status.PreferenceRef = &virtv1.InstancetypeStatusRef{
+			ControllerRevisionRef: &virtv1.ControllerRevisionRef{Name: "pref-status-rev"},
+		}
+
+		instancetypeSource, preferenceSource := apply.AppliedSourcesFor(vm)
+		Expect(instancetypeSource).To(HaveValue(Equal(apply.AppliedSource{Name: "it", RevisionName: "it-matcher-rev"})))
+		Expect(preferenceSource).To(HaveValue(Equal(apply.AppliedSource{Name: "pref", RevisionName: "pref-matcher-rev"})))
+	})
+
+	It("should fall back to the revisions captured within the status", func() {
+		vm.Spec.Instancetype = &virtv1.InstancetypeMatcher{Name: "it", Kind: instancetypeapi.SingularResourceName}
+		vm.Spec.Preference = &virtv1.PreferenceMatcher{Name: "pref"}
+		vm.Status.InstancetypeRef = &virtv1.InstancetypeStatusRef{
+			ControllerRevisionRef: &virtv1.ControllerRevisionRef{Name: "it-status-rev"},
+		}
+		vm.Status.PreferenceRef = &virtv1.InstancetypeStatusRef{}
+
+		instancetypeSource, preferenceSource := apply.AppliedSourcesFor(vm)
+		Expect(instancetypeSource).To(HaveValue(Equal(apply.AppliedSource{
+			Kind:         instancetypeapi.SingularResourceName,
+			Name:         "it",
+			RevisionName: "it-status-rev",
+		})))
+		Expect(preferenceSource).To(HaveValue(Equal(apply.AppliedSource{Name: "pref"})))
+	})
+})
@@ -26,6 +26,7 @@ import (
 )
 
 func applySchedulerName(
+	opts *applyOptions,
 	baseConflict *conflict.Conflict,
 	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
 	vmiSpec *virtv1.VirtualMachineInstanceSpec,
@@ -34,8 +35,8 @@ func applySchedulerName(
 		return nil
 	}
 
-	if vmiSpec.SchedulerName != "" {
-		return conflict.Conflicts{baseConflict.NewChild("schedulerName")}
+	if vmiSpec.SchedulerName != "" && vmiSpec.SchedulerName != instancetypeSpec.SchedulerName {
+		return opts.resolveConflicts(baseConflict.NewChild("schedulerName"))
 	}
 
 	vmiSpec.SchedulerName = instancetypeSpec.SchedulerName
@@ -10,6 +10,7 @@ import (
 	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
 
 	"kubevirt.io/kubevirt/pkg/instancetype/apply"
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
 	"kubevirt.io/kubevirt/pkg/libvmi"
 )
 
@@ -56,4 +57,51 @@ var _ = Describe("instancetype.spec.SchedulerName", func() {
 		Expect(conflicts).To(HaveLen(1))
 		Expect(conflicts[0].String()).To(Equal("spec.template.spec.schedulerName"))
 	})
+
+	It("should accept vmi.Spec.SchedulerName identical to instancetype.SchedulerName", func() {
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			SchedulerName: "ultra-fast-scheduler",
+		}
+		vmi.Spec.SchedulerName = "ultra-fast-scheduler"
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.SchedulerName).To(Equal("ultra-fast-scheduler"))
+	})
+
+	It("should keep vmi.Spec.SchedulerName and warn instead of conflicting with VMI overrides", func() {
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			SchedulerName: "ultra-fast-scheduler",
+		}
+		vmi.Spec.SchedulerName = "pinned-scheduler"
+
+		var warnings conflict.Conflicts
+		overridingApplier := apply.NewVMIApplier(
+			apply.WithVMIOverrides(),
+			apply.WithWarningHandler(func(warning *conflict.Conflict) {
+				warnings = append(warnings, warning)
+			}),
+		)
+		Expect(overridingApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+		Expect(vmi.Spec.SchedulerName).To(Equal("pinned-scheduler"))
+		Expect(warnings).To(Equal(conflict.Conflicts{conflict.New("spec", "template", "spec", "schedulerName")}))
+	})
+
+	It("should not warn with VMI overrides when vmi.Spec.SchedulerName is unset", func() {
+		instancetypeSpec = &v1beta1.VirtualMachineInstancetypeSpec{
+			SchedulerName: "ultra-fast-scheduler",
+		}
+
+		var warnings conflict.Conflicts
+		overridingApplier := apply.NewVMIApplier(
+			apply.WithVMIOverrides(),
+			apply.WithWarningHandler(func(warning *conflict.Conflict) {
+				warnings = append(warnings, warning)
+			}),
+		)
+		Expect(overridingApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+		Expect(vmi.Spec.SchedulerName).To(Equal("ultra-fast-scheduler"))
+		Expect(warnings).To(BeEmpty())
+	})
 })
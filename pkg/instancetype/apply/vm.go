@@ -81,7 +81,7 @@ func (a *vmApplier) ApplyToVM(vm *virtv1.VirtualMachine) error {
 		&vm.Spec.Template.Spec,
 		&vm.Spec.Template.ObjectMeta,
 	); len(conflicts) > 0 {
-		return fmt.Errorf("VM conflicts with instancetype spec in fields: [%s]", conflicts.String())
+		return fmt.Errorf("VM conflicts with instancetype spec in fields: [%s]", conflicts.JSONPath())
 	}
 	return nil
 }
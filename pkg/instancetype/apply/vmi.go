@@ -35,12 +35,21 @@ type preferenceApplier interface {
 
 type vmiApplier struct {
 	preferenceApplier preferenceApplier
+	options           applyOptions
 }
 
-func NewVMIApplier() *vmiApplier {
-	return &vmiApplier{
+// NewVMIApplier returns an applier configured by the provided options. The applier holds no state between calls
+// and only reads the provided instancetype and preference specs, so a single applier may be shared by goroutines
+// calling ApplyToVMI concurrently for different VMIs. Any warning handler or event sink must then also be safe for
+// concurrent use.
+func NewVMIApplier(opts ...Option) *vmiApplier {
+	applier := &vmiApplier{
 		preferenceApplier: preferenceApply.New(),
 	}
+	for _, opt := range opts {
+		opt(applier)
+	}
+	return applier
 }
 
 func (a *vmiApplier) ApplyToVMI(
@@ -49,29 +58,84 @@ func (a *vmiApplier) ApplyToVMI(
 	preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
 	vmiSpec *virtv1.VirtualMachineInstanceSpec,
 	vmiMetadata *metav1.ObjectMeta,
+) conflict.Conflicts {
+	return a.apply(&a.options, field, instancetypeSpec, preferenceSpec, vmiSpec, vmiMetadata)
+}
+
+func (a *vmiApplier) apply(
+	opts *applyOptions,
+	field *k8sfield.Path,
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+	vmiMetadata *metav1.ObjectMeta,
 ) conflict.Conflicts {
 	if instancetypeSpec == nil && preferenceSpec == nil {
 		return nil
 	}
 
+	recorder := newMutationRecorder(opts.eventSink, field, vmiSpec, vmiMetadata)
+
 	if instancetypeSpec != nil {
-		baseConflict := conflict.NewFromPath(field)
-		conflicts := conflict.Conflicts{}
-		conflicts = append(conflicts, applyNodeSelector(baseConflict, instancetypeSpec, vmiSpec)...)
-		conflicts = append(conflicts, applySchedulerName(baseConflict, instancetypeSpec, vmiSpec)...)
-		conflicts = append(conflicts, applyCPU(baseConflict, instancetypeSpec, preferenceSpec, vmiSpec)...)
-		conflicts = append(conflicts, applyMemory(baseConflict, instancetypeSpec, vmiSpec)...)
-		conflicts = append(conflicts, applyIOThreadPolicy(baseConflict, instancetypeSpec, vmiSpec)...)
-		conflicts = append(conflicts, applyLaunchSecurity(baseConflict, instancetypeSpec, vmiSpec)...)
-		conflicts = append(conflicts, applyGPUs(baseConflict, instancetypeSpec, vmiSpec)...)
-		conflicts = append(conflicts, applyHostDevices(baseConflict, instancetypeSpec, vmiSpec)...)
-		conflicts = append(conflicts, applyInstanceTypeAnnotations(instancetypeSpec.Annotations, vmiMetadata)...)
+		conflicts := applyInstancetype(opts, conflict.NewFromPath(field), instancetypeSpec, preferenceSpec, vmiSpec, vmiMetadata)
 		if len(conflicts) > 0 {
-			return conflicts
+			return conflicts.WithSource(conflict.SourceInstancetype).Sort()
 		}
+		recorder.record(MutationSourceInstancetype, opts.instancetypeSource, vmiSpec, vmiMetadata)
+		recorder = newMutationRecorder(opts.eventSink, field, vmiSpec, vmiMetadata)
 	}
 
+	efiProvidedByVMI := hasEFIBootloader(vmiSpec)
+	dedicatedIOThreadDefinedByVMI := disksDefiningDedicatedIOThread(vmiSpec)
 	a.preferenceApplier.Apply(preferenceSpec, vmiSpec, vmiMetadata)
+	if secureBootConflict := checkPreferredSecureBootSMM(field, efiProvidedByVMI, vmiSpec); secureBootConflict != nil {
+		return conflict.Conflicts{secureBootConflict}.WithSource(conflict.SourcePreference)
+	}
+	if ioThreadConflicts := checkPreferredDedicatedIOThreads(field, dedicatedIOThreadDefinedByVMI, vmiSpec); len(ioThreadConflicts) > 0 {
+		return ioThreadConflicts.WithSource(conflict.SourcePreference).Sort()
+	}
+	recorder.record(MutationSourcePreference, opts.preferenceSource, vmiSpec, vmiMetadata)
 
 	return nil
 }
+
+// applyInstancetype applies the fields of the instancetype within the categories selected by the options
+func applyInstancetype(
+	opts *applyOptions,
+	baseConflict *conflict.Conflict,
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec,
+	preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
+	vmiSpec *virtv1.VirtualMachineInstanceSpec,
+	vmiMetadata *metav1.ObjectMeta,
+) conflict.Conflicts {
+	conflicts := conflict.Conflicts{}
+	if opts.appliesCategory(FieldCategoryScheduling) {
+		conflicts = append(conflicts, applyNodeSelector(opts, baseConflict, instancetypeSpec, vmiSpec)...)
+		conflicts = append(conflicts, applySchedulerName(opts, baseConflict, instancetypeSpec, vmiSpec)...)
+	}
+	if opts.appliesCategory(FieldCategoryCPU) {
+		conflicts = append(conflicts, applyCPU(opts, baseConflict, instancetypeSpec, preferenceSpec, vmiSpec)...)
+	}
+	if opts.appliesCategory(FieldCategoryMemory) {
+		conflicts = append(conflicts, applyMemory(opts, baseConflict, instancetypeSpec, vmiSpec)...)
+	}
+	if opts.appliesCategory(FieldCategoryIOThreadsPolicy) {
+		conflicts = append(conflicts, applyIOThreadPolicy(opts, baseConflict, instancetypeSpec, vmiSpec)...)
+	}
+	if opts.appliesCategory(FieldCategoryLaunchSecurity) {
+		conflicts = append(conflicts, applyLaunchSecurity(opts, baseConflict, instancetypeSpec, vmiSpec)...)
+	}
+	if opts.appliesCategory(FieldCategoryGPUs) {
+		conflicts = append(conflicts, applyGPUs(opts, baseConflict, instancetypeSpec, vmiSpec)...)
+	}
+	if opts.appliesCategory(FieldCategoryHostDevices) {
+		conflicts = append(conflicts, applyHostDevices(opts, baseConflict, instancetypeSpec, vmiSpec)...)
+	}
+	if opts.appliesCategory(FieldCategoryAnnotations) {
+		conflicts = append(conflicts, applyInstanceTypeAnnotations(opts, instancetypeSpec.Annotations, vmiMetadata)...)
+		if opts.instancetypeSource != nil {
+			conflicts = append(conflicts, applyInstanceTypeLabels(opts, opts.instancetypeSource.Labels, vmiMetadata)...)
+		}
+	}
+	return conflicts
+}
@@ -20,16 +20,31 @@ package conflict
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 )
 
-const conflictsErrorFmt = "VM field(s) %s conflicts with selected instance type"
+const (
+	conflictsErrorFmt           = "VM field(s) %s conflicts with selected instance type"
+	preferenceConflictsErrorFmt = "VM field(s) %s conflicts with selected preference"
+)
+
+// Source identifies whether a conflict was caused by a value of the instance type or of the preference
+type Source string
+
+const (
+	SourceInstancetype Source = "instancetype"
+	SourcePreference   Source = "preference"
+)
 
 type Conflict struct {
 	Message string
+	// Source is set by the applier to tell users whether to fix the instance type or the preference
+	Source Source
 	k8sfield.Path
 }
 
@@ -54,15 +69,61 @@ func NewWithMessage(message, name string, moreNames ...string) *Conflict {
 
 func (c Conflict) NewChild(name string, moreNames ...string) *Conflict {
 	return &Conflict{
-		Path: *c.Child(name, moreNames...),
+		Path:   *c.Child(name, moreNames...),
+		Source: c.Source,
 	}
 }
 
+// Describe renders the path of the conflict followed by its source, if known, for display such as
+// spec.template.spec.domain.cpu.sockets (instancetype). String continues to render the path alone.
+func (c Conflict) Describe() string {
+	if c.Source == "" {
+		return c.String()
+	}
+	return fmt.Sprintf("%s (%s)", c.String(), c.Source)
+}
+
+// Error returns the message of the conflict or otherwise names the field and the source conflicting with it
 func (c Conflict) Error() string {
 	if c.Message != "" {
 		return c.Message
 	}
-	return fmt.Sprintf(conflictsErrorFmt, c.String())
+	if c.Source == SourcePreference {
+		return fmt.Sprintf(preferenceConflictsErrorFmt, c.JSONPath())
+	}
+	return fmt.Sprintf(conflictsErrorFmt, c.JSONPath())
+}
+
+// JSONPath renders the path of the conflict as a JSONPath-like string such as spec.template.spec.domain.cpu.sockets
+// for display, with list indices rendered as [0] and map keys quoted as ['key'] so they can not be mistaken for fields.
+func (c Conflict) JSONPath() string {
+	path := c.String()
+	var builder strings.Builder
+	for {
+		start := strings.IndexByte(path, '[')
+		if start == -1 {
+			builder.WriteString(path)
+			return builder.String()
+		}
+		end := strings.IndexByte(path[start:], ']')
+		if end == -1 {
+			builder.WriteString(path)
+			return builder.String()
+		}
+		end += start
+		builder.WriteString(path[:start])
+		if subscript := path[start+1 : end]; isIndex(subscript) {
+			builder.WriteString("[" + subscript + "]")
+		} else {
+			builder.WriteString("['" + subscript + "']")
+		}
+		path = path[end+1:]
+	}
+}
+
+func isIndex(subscript string) bool {
+	_, err := strconv.Atoi(subscript)
+	return err == nil
 }
 
 func (c Conflict) StatusCause() metav1.StatusCause {
@@ -87,8 +148,63 @@ func (c Conflicts) String() string {
 	return strings.Join(pathStrings, ", ")
 }
 
+// Describe renders each conflict as with Conflict.Describe
+func (c Conflicts) Describe() string {
+	descriptions := make([]string, 0, len(c))
+	for _, conflict := range c {
+		descriptions = append(descriptions, conflict.Describe())
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// JSONPath renders the path of each conflict as with Conflict.JSONPath
+func (c Conflicts) JSONPath() string {
+	pathStrings := make([]string, 0, len(c))
+	for _, conflict := range c {
+		pathStrings = append(pathStrings, conflict.JSONPath())
+	}
+	return strings.Join(pathStrings, ", ")
+}
+
+// Error describes the conflicts using their full JSONPath-like strings, such as
+// spec.template.spec.domain.cpu.cores, spec.template.spec.domain.cpu.sockets, sorted so that
+// the message is stable regardless of the order the conflicts were found in.
+// Conflicts that were all caused by the preference are reported against the selected preference.
 func (c Conflicts) Error() string {
-	return fmt.Sprintf(conflictsErrorFmt, c.String())
+	if len(c) > 0 && !slices.ContainsFunc(c, func(conflict *Conflict) bool { return conflict.Source != SourcePreference }) {
+		return fmt.Sprintf(preferenceConflictsErrorFmt, c.sortedJSONPath())
+	}
+	return fmt.Sprintf(conflictsErrorFmt, c.sortedJSONPath())
+}
+
+// WithSource sets the source of each conflict that does not have one yet, returning the conflicts
+func (c Conflicts) WithSource(source Source) Conflicts {
+	for _, conflict := range c {
+		if conflict.Source == "" {
+			conflict.Source = source
+		}
+	}
+	return c
+}
+
+// Sort orders the conflicts by their field path, keeping the order of conflicts with identical paths,
+// so that conflicts found while iterating over maps are always returned in the same order
+func (c Conflicts) Sort() Conflicts {
+	slices.SortStableFunc(c, func(a, b *Conflict) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return c
+}
+
+// sortedJSONPath renders the JSONPath-like string of each conflict sorted and without duplicates,
+// leaving the order of the conflicts themselves untouched
+func (c Conflicts) sortedJSONPath() string {
+	pathStrings := make([]string, 0, len(c))
+	for _, conflict := range c {
+		pathStrings = append(pathStrings, conflict.JSONPath())
+	}
+	slices.Sort(pathStrings)
+	return strings.Join(slices.Compact(pathStrings), ", ")
 }
 
 func (c Conflicts) StatusCauses() []metav1.StatusCause {
@@ -0,0 +1,174 @@
+package conflict_test
+
+import (
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+)
+
+var _ = Describe("Conflict", func() {
+	DescribeTable("should render a JSONPath-like string", func(path *k8sfield.Path, expected string) {
+		c := conflict.NewFromPath(path)
+		Expect(c.JSONPath()).To(Equal(expected))
+		Expect(c.String()).To(Equal(path.String()))
+	},
+		Entry("for a single field",
+			k8sfield.NewPath("spec"),
+			"spec",
+		),
+		Entry("for nested fields",
+			k8sfield.NewPath("spec", "template", "spec", "domain", "cpu", "sockets"),
+			"spec.template.spec.domain.cpu.sockets",
+		),
+		Entry("for an indexed field",
+			k8sfield.NewPath("spec", "template", "spec", "domain", "devices", "gpus").Index(0),
+			"spec.template.spec.domain.devices.gpus[0]",
+		),
+		Entry("for a field nested within an indexed field",
+			k8sfield.NewPath("spec", "template", "spec", "domain", "devices", "disks").Index(1).Child("disk", "bus"),
+			"spec.template.spec.domain.devices.disks[1].disk.bus",
+		),
+		Entry("for a keyed field",
+			k8sfield.NewPath("spec", "template", "metadata", "annotations").Key("kubevirt.io/annotation"),
+			"spec.template.metadata.annotations['kubevirt.io/annotation']",
+		),
+		Entry("for a keyed field within an indexed field",
+			k8sfield.NewPath("spec", "list").Index(2).Child("labels").Key("key"),
+			"spec.list[2].labels['key']",
+		),
+	)
+
+	It("should use the JSONPath-like string when describing the conflict", func() {
+		c := conflict.NewFromPath(k8sfield.NewPath("annotations").Key("kubevirt.io/annotation"))
+		Expect(c.Error()).To(Equal("VM field(s) annotations['kubevirt.io/annotation'] conflicts with selected instance type"))
+		Expect(c.StatusCause().Field).To(Equal("annotations[kubevirt.io/annotation]"))
+	})
+
+	It("should prefer any message when describing the conflict", func() {
+		c := conflict.NewWithMessage("message", "spec", "field")
+		Expect(c.Error()).To(Equal("message"))
+	})
+
+	It("should use the JSONPath-like strings when describing multiple conflicts", func() {
+		conflicts := conflict.Conflicts{
+			conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
+			conflict.NewFromPath(k8sfield.NewPath("spec", "template", "spec", "domain", "devices", "gpus").Index(0)),
+			conflict.NewFromPath(k8sfield.NewPath("annotations").Key("annotation")),
+		}
+		Expect(conflicts.JSONPath()).To(Equal(
+			"spec.template.spec.domain.cpu.sockets, spec.template.spec.domain.devices.gpus[0], annotations['annotation']"))
+		Expect(conflicts.Error()).To(Equal("VM field(s) annotations['annotation'], spec.template.spec.domain.cpu.sockets, " +
+			"spec.template.spec.domain.devices.gpus[0] conflicts with selected instance type"))
+	})
+
+	It("should sort the full paths of multiple conflicts", func() {
+		conflicts := conflict.Conflicts{
+			conflict.New("spec", "template", "spec", "domain", "memory"),
+			conflict.New("spec", "template", "spec", "domain", "cpu", "threads"),
+			conflict.NewFromPath(k8sfield.NewPath("spec", "template", "spec", "domain", "devices", "gpus").Index(1)),
+			conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
+			conflict.NewFromPath(k8sfield.NewPath("spec", "template", "spec", "nodeSelector").Key("kubevirt.io/zone")),
+			conflict.New("spec", "template", "spec", "domain", "cpu", "cores"),
+			conflict.NewFromPath(k8sfield.NewPath("spec", "template", "spec", "domain", "devices", "gpus").Index(0)),
+			conflict.NewFromPath(k8sfield.NewPath("spec", "template", "spec", "nodeSelector").Key("node-type")),
+		}
+		original := slices.Clone(conflicts)
+
+		Expect(conflicts.Error()).To(Equal("VM field(s) " +
+			"spec.template.spec.domain.cpu.cores, spec.template.spec.domain.cpu.sockets, spec.template.spec.domain.cpu.threads, " +
+			"spec.template.spec.domain.devices.gpus[0], spec.template.spec.domain.devices.gpus[1], " +
+			"spec.template.spec.domain.memory, " +
+			"spec.template.spec.nodeSelector['kubevirt.io/zone'], spec.template.spec.nodeSelector['node-type'] " +
+			"conflicts with selected instance type"))
+		Expect(conflicts).To(Equal(original))
+	})
+
+	It("should describe conflicts identically regardless of their order", func() {
+		conflicts := conflict.Conflicts{
+			conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
+			conflict.New("spec", "template", "spec", "domain", "memory"),
+			conflict.New("spec", "template", "spec", "domain", "cpu", "cores"),
+		}
+		reversed := slices.Clone(conflicts)
+		slices.Reverse(reversed)
+
+		Expect(reversed.Error()).To(Equal(conflicts.Error()))
+	})
+
+	It("should sort conflicts by their field path", func() {
+		conflicts := conflict.Conflicts{
+			conflict.NewFromPath(k8sfield.NewPath("spec", "template", "spec", "nodeSelector").Key("node-type")),
+			conflict.New("spec", "template", "spec", "domain", "memory"),
+			conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
+			conflict.NewFromPath(k8sfield.NewPath("spec", "template", "spec", "nodeSelector").Key("kubevirt.io/zone")),
+		}
+		Expect(conflicts.Sort().String()).To(Equal(
+			"spec.template.spec.domain.cpu.sockets, " +
+				"spec.template.spec.domain.memory, " +
+				"spec.template.spec.nodeSelector[kubevirt.io/zone], " +
+				"spec.template.spec.nodeSelector[node-type]"))
+	})
+
+	Context("with a source", func() {
+		It("should name the preference when describing a conflict caused by it", func() {
+			c := conflict.New("spec", "template", "spec", "domain", "features", "smm")
+			c.Source = conflict.SourcePreference
+			Expect(c.Error()).To(Equal("VM field(s) spec.template.spec.domain.features.smm conflicts with selected preference"))
+			Expect(c.String()).To(Equal("spec.template.spec.domain.features.smm"))
+			Expect(c.Describe()).To(Equal("spec.template.spec.domain.features.smm (preference)"))
+		})
+
+		It("should name the preference when describing multiple conflicts all caused by it", func() {
+			conflicts := conflict.Conflicts{
+				conflict.New("spec", "template", "spec", "domain", "cpu", "sockets"),
+				conflict.New("spec", "template", "spec", "domain", "cpu", "cores"),
+			}.WithSource(conflict.SourcePreference)
+			Expect(conflicts.Error()).To(Equal(
+				"VM field(s) spec.template.spec.domain.cpu.cores, spec.template.spec.domain.cpu.sockets conflicts with selected preference"))
+		})
+
+		It("should name the instance type when describing conflicts caused by it", func() {
+			conflicts := conflict.Conflicts{
+				conflict.New("spec", "template", "spec", "domain", "memory"),
+			}.WithSource(conflict.SourceInstancetype)
+			Expect(conflicts[0].Error()).To(Equal("VM field(s) spec.template.spec.domain.memory conflicts with selected instance type"))
+			Expect(conflicts.Error()).To(Equal("VM field(s) spec.template.spec.domain.memory conflicts with selected instance type"))
+		})
+
+		It("should describe the source of each conflict while rendering only paths with String", func() {
+			preferenceConflict := conflict.New("spec", "template", "spec", "domain", "features", "smm")
+			preferenceConflict.Source = conflict.SourcePreference
+			conflicts := conflict.Conflicts{
+				conflict.New("spec", "template", "spec", "domain", "memory"),
+				preferenceConflict,
+				conflict.New("spec", "template", "spec", "domain", "cpu"),
+			}
+			conflicts[0].Source = conflict.SourceInstancetype
+			Expect(conflicts.Describe()).To(Equal(
+				"spec.template.spec.domain.memory (instancetype), spec.template.spec.domain.features.smm (preference), " +
+					"spec.template.spec.domain.cpu"))
+			Expect(conflicts.String()).To(Equal(
+				"spec.template.spec.domain.memory, spec.template.spec.domain.features.smm, spec.template.spec.domain.cpu"))
+		})
+
+		It("should keep an existing source when setting the source of conflicts", func() {
+			preferenceConflict := conflict.New("spec", "preference")
+			preferenceConflict.Source = conflict.SourcePreference
+			conflicts := conflict.Conflicts{preferenceConflict, conflict.New("spec", "instancetype")}.WithSource(conflict.SourceInstancetype)
+
+			Expect(conflicts[0].Source).To(Equal(conflict.SourcePreference))
+			Expect(conflicts[1].Source).To(Equal(conflict.SourceInstancetype))
+		})
+
+		It("should be inherited by child conflicts", func() {
+			base := conflict.New("spec")
+			base.Source = conflict.SourceInstancetype
+			Expect(base.NewChild("schedulerName").Source).To(Equal(conflict.SourceInstancetype))
+		})
+	})
+})
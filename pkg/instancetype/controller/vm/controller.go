@@ -21,6 +21,8 @@ package vm
 import (
 	"context"
 	"fmt"
+	"maps"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -111,6 +113,11 @@ const (
 	cleanControllerRevisionErrFmt   = "error encountered cleaning controllerRevision %s after successfully expanding VirtualMachine %s: %v"
 )
 
+const (
+	appliedToVMIReason   = "AppliedToVMI"
+	appliedToVMIEventFmt = "%s %s applied to VirtualMachineInstance fields: %s"
+)
+
 func (c *controller) Sync(vm *virtv1.VirtualMachine, vmi *virtv1.VirtualMachineInstance) (*virtv1.VirtualMachine, error) {
 	if vm.Spec.Instancetype == nil && vm.Spec.Preference == nil {
 		return vm, nil
@@ -246,15 +253,56 @@ func (c *controller) ApplyToVMI(vm *virtv1.VirtualMachine, vmi *virtv1.VirtualMa
 	annotations.Set(vm, vmi)
 	preferenceannotations.Set(vm, vmi)
 
-	if conflicts := apply.NewVMIApplier().ApplyToVMI(
+	instancetypeSource, preferenceSource := apply.AppliedSourcesFor(vm)
+	sink := &mutationEventSink{}
+	result := apply.NewVMIApplier(apply.WithEventSink(sink)).ApplyToVMIWithResult(
 		k8sfield.NewPath("spec"),
+		instancetypeSource,
 		instancetypeSpec,
+		preferenceSource,
 		preferenceSpec,
 		&vmi.Spec,
 		&vmi.ObjectMeta,
-	); len(conflicts) > 0 {
-		return fmt.Errorf("VMI conflicts with instancetype spec in fields: [%s]", conflicts.String())
+	)
+	if len(result.Conflicts) > 0 {
+		return fmt.Errorf("VMI conflicts with instancetype spec in fields: [%s]", result.Conflicts.JSONPath())
 	}
 
+	// Record the revisions applied to help tell which version of the instancetype and preference produced the VMI
+	if vmi.Annotations == nil {
+		vmi.Annotations = make(map[string]string)
+	}
+	maps.Copy(vmi.Annotations, result.Annotations())
+
+	c.recordMutations(vm, sink.events)
+
 	return nil
 }
+
+// mutationEventSink collects each field of the VMI mutated by the instancetype and preference
+type mutationEventSink struct {
+	events []apply.MutationEvent
+}
+
+func (s *mutationEventSink) RecordMutation(event apply.MutationEvent) {
+	s.events = append(s.events, event)
+}
+
+// recordMutations emits a single event on the VM for the instancetype and preference listing the fields of the VMI each mutated
+func (c *controller) recordMutations(vm *virtv1.VirtualMachine, events []apply.MutationEvent) {
+	for _, source := range []apply.MutationSource{apply.MutationSourceInstancetype, apply.MutationSourcePreference} {
+		var (
+			name   string
+			fields []string
+		)
+		for _, event := range events {
+			if event.Source == source {
+				name = event.Name
+				fields = append(fields, event.Field.String())
+			}
+		}
+		if len(fields) > 0 {
+			c.recorder.Eventf(vm, corev1.EventTypeNormal, appliedToVMIReason, appliedToVMIEventFmt, source, name, strings.Join(fields, ", "))
+		}
+	}
+}
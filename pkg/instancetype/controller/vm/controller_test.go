@@ -29,6 +29,7 @@ import (
 	"kubevirt.io/client-go/kubecli"
 	"kubevirt.io/client-go/kubevirt/fake"
 
+	"kubevirt.io/kubevirt/pkg/instancetype/apply"
 	instancetypecontroller "kubevirt.io/kubevirt/pkg/instancetype/controller/vm"
 	"kubevirt.io/kubevirt/pkg/instancetype/revision"
 	"kubevirt.io/kubevirt/pkg/libvmi"
@@ -481,9 +482,15 @@ var _ = Describe("Instance type and Preference VirtualMachine Controller", func(
 			Expect(vmi.Spec.Domain.CPU.Sockets).To(Equal(clusterInstancetypeObj.Spec.CPU.Guest))
 			Expect(vmi.Spec.Domain.Memory.Guest).To(HaveValue(Equal(clusterInstancetypeObj.Spec.Memory.Guest)))
 			Expect(vmi.Annotations).To(HaveKeyWithValue(virtv1.ClusterInstancetypeAnnotation, clusterInstancetypeObj.Name))
+			Expect(vmi.Annotations).To(HaveKeyWithValue(apply.InstancetypeRevisionAnnotation, instancetypeRevision.Name))
 			Expect(vmi.Annotations).ToNot(HaveKey(virtv1.PreferenceAnnotation))
 			Expect(vmi.Annotations).ToNot(HaveKey(virtv1.InstancetypeAnnotation))
 			Expect(vmi.Annotations).ToNot(HaveKey(virtv1.ClusterPreferenceAnnotation))
+			Expect(vmi.Annotations).ToNot(HaveKey(apply.PreferenceRevisionAnnotation))
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring("Instancetype %s applied", clusterInstancetypeObj.Name),
+				ContainSubstring("spec.domain.cpu.sockets"),
+			)))
 		})
 
 		DescribeTable("should fail to sync with FailedFindInstancetype reason",
@@ -0,0 +1,105 @@
+package expand_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	virtv1 "kubevirt.io/api/core/v1"
+	v1beta1 "kubevirt.io/api/instancetype/v1beta1"
+
+	"kubevirt.io/kubevirt/pkg/instancetype/conflict"
+	"kubevirt.io/kubevirt/pkg/instancetype/expand"
+	"kubevirt.io/kubevirt/pkg/testutils"
+)
+
+// Set UPDATE_GOLDEN to regenerate testdata/vm_expanded.yaml after an intended change in behaviour
+const updateGoldenEnv = "UPDATE_GOLDEN"
+
+type fakeFinder struct {
+	instancetypeSpec *v1beta1.VirtualMachineInstancetypeSpec
+	preferenceSpec   *v1beta1.VirtualMachinePreferenceSpec
+}
+
+func (f *fakeFinder) Find(_ *virtv1.VirtualMachine) (*v1beta1.VirtualMachineInstancetypeSpec, error) {
+	return f.instancetypeSpec, nil
+}
+
+func (f *fakeFinder) FindPreference(_ *virtv1.VirtualMachine) (*v1beta1.VirtualMachinePreferenceSpec, error) {
+	return f.preferenceSpec, nil
+}
+
+var _ = Describe("Expand", func() {
+	const testdataDir = "testdata"
+
+	var (
+		vm     *virtv1.VirtualMachine
+		finder *fakeFinder
+	)
+
+	readYAML := func(name string, obj interface{}) {
+		data, err := os.ReadFile(filepath.Join(testdataDir, name))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(yaml.UnmarshalStrict(data, obj)).To(Succeed())
+	}
+
+	expandVM := func(vm *virtv1.VirtualMachine) (*virtv1.VirtualMachine, error) {
+		clusterConfig, _, _ := testutils.NewFakeClusterConfigUsingKVConfig(&virtv1.KubeVirtConfiguration{})
+		return expand.New(clusterConfig, finder, finder).Expand(vm)
+	}
+
+	BeforeEach(func() {
+		vm = &virtv1.VirtualMachine{}
+		readYAML("vm.yaml", vm)
+
+		instancetype := &v1beta1.VirtualMachineInstancetype{}
+		readYAML("instancetype.yaml", instancetype)
+		preference := &v1beta1.VirtualMachinePreference{}
+		readYAML("preference.yaml", preference)
+
+		finder = &fakeFinder{
+			instancetypeSpec: &instancetype.Spec,
+			preferenceSpec:   &preference.Spec,
+		}
+	})
+
+	It("should match the golden expanded VM", func() {
+		expandedVM, err := expandVM(vm)
+		Expect(err).ToNot(HaveOccurred())
+
+		expanded, err := yaml.Marshal(expandedVM)
+		Expect(err).ToNot(HaveOccurred())
+
+		goldenPath := filepath.Join(testdataDir, "vm_expanded.yaml")
+		if _, update := os.LookupEnv(updateGoldenEnv); update {
+			Expect(os.WriteFile(goldenPath, expanded, 0o600)).To(Succeed())
+		}
+		golden, err := os.ReadFile(goldenPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(expanded)).To(Equal(string(golden)))
+	})
+
+	It("should not mutate the provided VM", func() {
+		originalVM := vm.DeepCopy()
+
+		_, err := expandVM(vm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vm).To(Equal(originalVM))
+	})
+
+	It("should return conflicts without mutating the provided VM", func() {
+		vm.Spec.Template.Spec.NodeSelector = map[string]string{"node-type": "storage"}
+		originalVM := vm.DeepCopy()
+
+		_, err := expandVM(vm)
+		Expect(err).To(MatchError(conflict.Conflicts{
+			conflict.NewFromPath(k8sfield.NewPath("spec", "template", "spec", "nodeSelector").Key("node-type")),
+		}.WithSource(conflict.SourceInstancetype)))
+		Expect(vm).To(Equal(originalVM))
+	})
+})
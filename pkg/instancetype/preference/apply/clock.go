@@ -39,7 +39,34 @@ func applyClockPreferences(preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
 		vmiSpec.Domain.Clock.ClockOffset = *preferenceSpec.Clock.PreferredClockOffset.DeepCopy()
 	}
 
-	if preferenceSpec.Clock.PreferredTimer != nil && vmiSpec.Domain.Clock.Timer == nil {
-		vmiSpec.Domain.Clock.Timer = preferenceSpec.Clock.PreferredTimer.DeepCopy()
+	if preferenceSpec.Clock.PreferredTimer != nil {
+		applyTimerPreferences(preferenceSpec.Clock.PreferredTimer, vmiSpec)
+	}
+}
+
+// applyTimerPreferences merges each preferred timer individually, only applying those not already provided by the VMI
+func applyTimerPreferences(preferredTimer *virtv1.Timer, vmiSpec *virtv1.VirtualMachineInstanceSpec) {
+	if vmiSpec.Domain.Clock.Timer == nil {
+		vmiSpec.Domain.Clock.Timer = &virtv1.Timer{}
+	}
+
+	if preferredTimer.HPET != nil && vmiSpec.Domain.Clock.Timer.HPET == nil {
+		vmiSpec.Domain.Clock.Timer.HPET = preferredTimer.HPET.DeepCopy()
+	}
+
+	if preferredTimer.KVM != nil && vmiSpec.Domain.Clock.Timer.KVM == nil {
+		vmiSpec.Domain.Clock.Timer.KVM = preferredTimer.KVM.DeepCopy()
+	}
+
+	if preferredTimer.PIT != nil && vmiSpec.Domain.Clock.Timer.PIT == nil {
+		vmiSpec.Domain.Clock.Timer.PIT = preferredTimer.PIT.DeepCopy()
+	}
+
+	if preferredTimer.RTC != nil && vmiSpec.Domain.Clock.Timer.RTC == nil {
+		vmiSpec.Domain.Clock.Timer.RTC = preferredTimer.RTC.DeepCopy()
+	}
+
+	if preferredTimer.Hyperv != nil && vmiSpec.Domain.Clock.Timer.Hyperv == nil {
+		vmiSpec.Domain.Clock.Timer.Hyperv = preferredTimer.Hyperv.DeepCopy()
 	}
 }
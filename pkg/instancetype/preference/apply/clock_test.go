@@ -47,4 +47,57 @@ var _ = Describe("Preference.Clock", func() {
 		Expect(vmi.Spec.Domain.Clock.ClockOffset).To(Equal(*preferenceSpec.Clock.PreferredClockOffset))
 		Expect(vmi.Spec.Domain.Clock.Timer).To(HaveValue(Equal(*preferenceSpec.Clock.PreferredTimer)))
 	})
+
+	It("should merge preferred timers with a partial clock provided by the VMI", func() {
+		vmi.Spec.Domain.Clock = &virtv1.Clock{
+			ClockOffset: virtv1.ClockOffset{
+				Timezone: pointer.P(virtv1.ClockOffsetTimezone("Europe/London")),
+			},
+			Timer: &virtv1.Timer{
+				HPET: &virtv1.HPETTimer{
+					Enabled: pointer.P(false),
+				},
+				RTC: &virtv1.RTCTimer{
+					TickPolicy: virtv1.RTCTickPolicyCatchup,
+				},
+			},
+		}
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			Clock: &v1beta1.ClockPreferences{
+				PreferredClockOffset: &virtv1.ClockOffset{
+					UTC: &virtv1.ClockOffsetUTC{},
+				},
+				PreferredTimer: &virtv1.Timer{
+					HPET: &virtv1.HPETTimer{
+						TickPolicy: virtv1.HPETTickPolicyDelay,
+					},
+					PIT: &virtv1.PITTimer{
+						TickPolicy: virtv1.PITTickPolicyDelay,
+					},
+					RTC: &virtv1.RTCTimer{
+						TickPolicy: virtv1.RTCTickPolicyDelay,
+					},
+					Hyperv: &virtv1.HypervTimer{},
+				},
+			},
+		}
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+		Expect(vmi.Spec.Domain.Clock.ClockOffset).To(Equal(virtv1.ClockOffset{
+			Timezone: pointer.P(virtv1.ClockOffsetTimezone("Europe/London")),
+		}))
+		Expect(vmi.Spec.Domain.Clock.Timer).To(HaveValue(Equal(virtv1.Timer{
+			HPET: &virtv1.HPETTimer{
+				Enabled: pointer.P(false),
+			},
+			PIT: &virtv1.PITTimer{
+				TickPolicy: virtv1.PITTickPolicyDelay,
+			},
+			RTC: &virtv1.RTCTimer{
+				TickPolicy: virtv1.RTCTickPolicyCatchup,
+			},
+			Hyperv: &virtv1.HypervTimer{},
+		})))
+	})
 })
@@ -27,7 +27,8 @@ func applyCPUPreferences(preferenceSpec *v1beta1.VirtualMachinePreferenceSpec, v
 	if preferenceSpec.CPU == nil || len(preferenceSpec.CPU.PreferredCPUFeatures) == 0 {
 		return
 	}
-	// Only apply any preferred CPU features when the same feature has not been provided by a user already
+	// Only apply any preferred CPU features when the same feature has not been provided by a user already,
+	// or earlier in the list of preferred CPU features by a preference that has not been validated on admission
 	cpuFeatureNames := make(map[string]struct{})
 	for _, cpuFeature := range vmiSpec.Domain.CPU.Features {
 		cpuFeatureNames[cpuFeature.Name] = struct{}{}
@@ -35,6 +36,7 @@ func applyCPUPreferences(preferenceSpec *v1beta1.VirtualMachinePreferenceSpec, v
 	for _, preferredCPUFeature := range preferenceSpec.CPU.PreferredCPUFeatures {
 		if _, foundCPUFeature := cpuFeatureNames[preferredCPUFeature.Name]; !foundCPUFeature {
 			vmiSpec.Domain.CPU.Features = append(vmiSpec.Domain.CPU.Features, preferredCPUFeature)
+			cpuFeatureNames[preferredCPUFeature.Name] = struct{}{}
 		}
 	}
 }
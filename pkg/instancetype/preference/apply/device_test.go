@@ -154,6 +154,29 @@ var _ = Describe("Preference.Devices", func() {
 			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
 			Expect(vmi.Spec.Domain.Devices.Interfaces[0].Masquerade).To(BeNil())
 		})
+		It("should only be applied to interfaces on the Pod network without an explicit binding", func() {
+			vmi.Spec.Domain.Devices.Interfaces = []virtv1.Interface{
+				{Name: "implicit"},
+				{Name: "bridge", InterfaceBindingMethod: virtv1.InterfaceBindingMethod{Bridge: &virtv1.InterfaceBridge{}}},
+				{Name: "passt", Binding: &virtv1.PluginBinding{Name: "passt"}},
+				{Name: "implicit-multus"},
+			}
+			vmi.Spec.Networks = []virtv1.Network{
+				{Name: "implicit", NetworkSource: virtv1.NetworkSource{Pod: &virtv1.PodNetwork{}}},
+				{Name: "bridge", NetworkSource: virtv1.NetworkSource{Pod: &virtv1.PodNetwork{}}},
+				{Name: "passt", NetworkSource: virtv1.NetworkSource{Pod: &virtv1.PodNetwork{}}},
+				{Name: "implicit-multus", NetworkSource: virtv1.NetworkSource{Multus: &virtv1.MultusNetwork{NetworkName: "net"}}},
+			}
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+			interfaces := vmi.Spec.Domain.Devices.Interfaces
+			Expect(interfaces[0].Masquerade).ToNot(BeNil())
+			Expect(interfaces[1].Masquerade).To(BeNil())
+			Expect(interfaces[1].Bridge).ToNot(BeNil())
+			Expect(interfaces[2].Masquerade).To(BeNil())
+			Expect(interfaces[2].Binding).To(HaveValue(Equal(virtv1.PluginBinding{Name: "passt"})))
+			Expect(interfaces[3].Masquerade).To(BeNil())
+		})
 	})
 
 	It("should apply to VMI", func() {
@@ -247,6 +270,437 @@ var _ = Describe("Preference.Devices", func() {
 		})
 	})
 
+	Context("PreferredNetworkInterfaceMultiQueue and PreferredBlockMultiQueue", func() {
+		DescribeTable("should preserve the nil, false and true states", func(vmiValue, preferenceValue, expectedValue *bool) {
+			vmi.Spec.Domain.Devices.NetworkInterfaceMultiQueue = vmiValue
+			vmi.Spec.Domain.Devices.BlockMultiQueue = vmiValue
+			preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+				Devices: &v1beta1.DevicePreferences{
+					PreferredNetworkInterfaceMultiQueue: preferenceValue,
+					PreferredBlockMultiQueue:            preferenceValue,
+				},
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.NetworkInterfaceMultiQueue).To(Equal(expectedValue))
+			Expect(vmi.Spec.Domain.Devices.BlockMultiQueue).To(Equal(expectedValue))
+		},
+			Entry("unset by both", nil, nil, nil),
+			Entry("unset by the VMI and enabled by the preference", nil, pointer.P(true), pointer.P(true)),
+			Entry("unset by the VMI and disabled by the preference", nil, pointer.P(false), pointer.P(false)),
+			Entry("disabled by the VMI and enabled by the preference", pointer.P(false), pointer.P(true), pointer.P(false)),
+			Entry("enabled by the VMI and disabled by the preference", pointer.P(true), pointer.P(false), pointer.P(true)),
+			Entry("enabled by the VMI and unset by the preference", pointer.P(true), nil, pointer.P(true)),
+			Entry("disabled by the VMI and unset by the preference", pointer.P(false), nil, pointer.P(false)),
+		)
+
+		It("should not share the preference value with the VMI", func() {
+			preferenceSpec.Devices.PreferredNetworkInterfaceMultiQueue = pointer.P(true)
+			preferenceSpec.Devices.PreferredBlockMultiQueue = pointer.P(true)
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.NetworkInterfaceMultiQueue).ToNot(BeIdenticalTo(preferenceSpec.Devices.PreferredNetworkInterfaceMultiQueue))
+			Expect(vmi.Spec.Domain.Devices.BlockMultiQueue).ToNot(BeIdenticalTo(preferenceSpec.Devices.PreferredBlockMultiQueue))
+		})
+	})
+
+	Context("PreferredAutoattach", func() {
+		type vmiAutoattach func(*virtv1.Devices) **bool
+		type preferredAutoattach func(*v1beta1.DevicePreferences) **bool
+
+		autoattachEntries := func(name string, vmiField vmiAutoattach, preferenceField preferredAutoattach) []TableEntry {
+			return []TableEntry{
+				Entry(name+" unset by both", vmiField, preferenceField, nil, nil, nil),
+				Entry(name+" unset by the VMI and enabled by the preference", vmiField, preferenceField, nil, pointer.P(true), pointer.P(true)),
+				Entry(name+" unset by the VMI and disabled by the preference", vmiField, preferenceField, nil, pointer.P(false), pointer.P(false)),
+				Entry(name+" disabled by the VMI and enabled by the preference",
+					vmiField, preferenceField, pointer.P(false), pointer.P(true), pointer.P(false)),
+				Entry(name+" enabled by the VMI and disabled by the preference",
+					vmiField, preferenceField, pointer.P(true), pointer.P(false), pointer.P(true)),
+			}
+		}
+
+		DescribeTable("should preserve the nil, false and true states of", func(
+			vmiField vmiAutoattach, preferenceField preferredAutoattach, vmiValue, preferenceValue, expectedValue *bool,
+		) {
+			preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+				Devices: &v1beta1.DevicePreferences{},
+			}
+			*vmiField(&vmi.Spec.Domain.Devices) = vmiValue
+			*preferenceField(preferenceSpec.Devices) = preferenceValue
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(*vmiField(&vmi.Spec.Domain.Devices)).To(Equal(expectedValue))
+			if expectedValue != nil && vmiValue == nil {
+				Expect(*vmiField(&vmi.Spec.Domain.Devices)).ToNot(BeIdenticalTo(preferenceValue))
+			}
+		},
+			autoattachEntries("AutoattachSerialConsole",
+				func(d *virtv1.Devices) **bool { return &d.AutoattachSerialConsole },
+				func(p *v1beta1.DevicePreferences) **bool { return &p.PreferredAutoattachSerialConsole },
+			),
+			autoattachEntries("AutoattachGraphicsDevice",
+				func(d *virtv1.Devices) **bool { return &d.AutoattachGraphicsDevice },
+				func(p *v1beta1.DevicePreferences) **bool { return &p.PreferredAutoattachGraphicsDevice },
+			),
+			autoattachEntries("AutoattachPodInterface",
+				func(d *virtv1.Devices) **bool { return &d.AutoattachPodInterface },
+				func(p *v1beta1.DevicePreferences) **bool { return &p.PreferredAutoattachPodInterface },
+			),
+			autoattachEntries("AutoattachMemBalloon",
+				func(d *virtv1.Devices) **bool { return &d.AutoattachMemBalloon },
+				func(p *v1beta1.DevicePreferences) **bool { return &p.PreferredAutoattachMemBalloon },
+			),
+		)
+	})
+
+	Context("PreferredInputBus and PreferredInputType", func() {
+		DescribeTable("should apply defaults to input devices", func(vmiInput, expectedInput virtv1.Input) {
+			vmi.Spec.Domain.Devices.Inputs = []virtv1.Input{vmiInput}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.Inputs).To(ConsistOf(expectedInput))
+		},
+			Entry("with a tablet not providing a bus",
+				virtv1.Input{Name: "tablet", Type: virtv1.InputTypeTablet},
+				virtv1.Input{Name: "tablet", Type: virtv1.InputTypeTablet, Bus: virtv1.InputBusVirtio},
+			),
+			Entry("with a tablet providing a bus",
+				virtv1.Input{Name: "tablet", Type: virtv1.InputTypeTablet, Bus: virtv1.InputBusUSB},
+				virtv1.Input{Name: "tablet", Type: virtv1.InputTypeTablet, Bus: virtv1.InputBusUSB},
+			),
+			Entry("with a keyboard not providing a bus",
+				virtv1.Input{Name: "keyboard", Type: virtv1.InputTypeKeyboard},
+				virtv1.Input{Name: "keyboard", Type: virtv1.InputTypeKeyboard, Bus: virtv1.InputBusVirtio},
+			),
+			Entry("with a keyboard providing a bus",
+				virtv1.Input{Name: "keyboard", Type: virtv1.InputTypeKeyboard, Bus: virtv1.InputBusUSB},
+				virtv1.Input{Name: "keyboard", Type: virtv1.InputTypeKeyboard, Bus: virtv1.InputBusUSB},
+			),
+			Entry("with an input not providing a bus or type",
+				virtv1.Input{Name: "input"},
+				virtv1.Input{Name: "input", Type: virtv1.InputTypeTablet, Bus: virtv1.InputBusVirtio},
+			),
+		)
+	})
+
+	Context("PreferredInterfaceModel", func() {
+		It("should be applied to all eligible interfaces without a model", func() {
+			vmi.Spec.Domain.Devices.Interfaces = []virtv1.Interface{
+				{
+					Name: "unset",
+				},
+				{
+					Name:                   "bridge",
+					InterfaceBindingMethod: virtv1.InterfaceBindingMethod{Bridge: &virtv1.InterfaceBridge{}},
+				},
+				{
+					Name:                   "masquerade",
+					InterfaceBindingMethod: virtv1.InterfaceBindingMethod{Masquerade: &virtv1.InterfaceMasquerade{}},
+				},
+				{
+					Name:    "plugin",
+					Binding: &virtv1.PluginBinding{Name: "plugin"},
+				},
+				{
+					Name:                   "sriov",
+					InterfaceBindingMethod: virtv1.InterfaceBindingMethod{SRIOV: &virtv1.InterfaceSRIOV{}},
+				},
+				{
+					Name:                   "explicit",
+					Model:                  "e1000",
+					InterfaceBindingMethod: virtv1.InterfaceBindingMethod{Bridge: &virtv1.InterfaceBridge{}},
+				},
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+			ifaces := vmi.Spec.Domain.Devices.Interfaces
+			Expect(ifaces[0].Model).To(Equal(preferenceSpec.Devices.PreferredInterfaceModel))
+			Expect(ifaces[1].Model).To(Equal(preferenceSpec.Devices.PreferredInterfaceModel))
+			Expect(ifaces[2].Model).To(Equal(preferenceSpec.Devices.PreferredInterfaceModel))
+			Expect(ifaces[3].Model).To(Equal(preferenceSpec.Devices.PreferredInterfaceModel))
+			Expect(ifaces[4].Model).To(BeEmpty())
+			Expect(ifaces[5].Model).To(Equal("e1000"))
+		})
+
+		It("should preserve the state of interfaces", func() {
+			vmi.Spec.Domain.Devices.Interfaces = []virtv1.Interface{
+				{
+					Name:                   "explicit",
+					State:                  virtv1.InterfaceStateAbsent,
+					InterfaceBindingMethod: virtv1.InterfaceBindingMethod{Bridge: &virtv1.InterfaceBridge{}},
+				},
+				{
+					Name:                   "unset",
+					InterfaceBindingMethod: virtv1.InterfaceBindingMethod{Bridge: &virtv1.InterfaceBridge{}},
+				},
+			}
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+			ifaces := vmi.Spec.Domain.Devices.Interfaces
+			Expect(ifaces[0].State).To(Equal(virtv1.InterfaceStateAbsent))
+			Expect(ifaces[0].Model).To(Equal(preferenceSpec.Devices.PreferredInterfaceModel))
+			Expect(ifaces[1].State).To(BeEmpty())
+			Expect(ifaces[1].Model).To(Equal(preferenceSpec.Devices.PreferredInterfaceModel))
+		})
+	})
+
+	Context("with hotplugged volumes", func() {
+		BeforeEach(func() {
+			vmi.Spec.Domain.Devices.Disks = []virtv1.Disk{
+				{
+					Name: "boot",
+					DiskDevice: virtv1.DiskDevice{
+						Disk: &virtv1.DiskTarget{},
+					},
+				},
+				{
+					Name: "hotplug-pvc",
+					DiskDevice: virtv1.DiskDevice{
+						Disk: &virtv1.DiskTarget{},
+					},
+				},
+				{
+					Name: "hotplug-dv",
+				},
+				{
+					Name: "hotplug-explicit",
+					DiskDevice: virtv1.DiskDevice{
+						Disk: &virtv1.DiskTarget{
+							Bus: virtv1.DiskBusSCSI,
+						},
+					},
+				},
+			}
+			vmi.Spec.Volumes = []virtv1.Volume{
+				{
+					Name: "boot",
+					VolumeSource: virtv1.VolumeSource{
+						ContainerDisk: &virtv1.ContainerDiskSource{},
+					},
+				},
+				{
+					Name: "hotplug-pvc",
+					VolumeSource: virtv1.VolumeSource{
+						PersistentVolumeClaim: &virtv1.PersistentVolumeClaimVolumeSource{
+							Hotpluggable: true,
+						},
+					},
+				},
+				{
+					Name: "hotplug-dv",
+					VolumeSource: virtv1.VolumeSource{
+						DataVolume: &virtv1.DataVolumeSource{
+							Hotpluggable: true,
+						},
+					},
+				},
+				{
+					Name: "hotplug-explicit",
+					VolumeSource: virtv1.VolumeSource{
+						PersistentVolumeClaim: &virtv1.PersistentVolumeClaimVolumeSource{
+							Hotpluggable: true,
+						},
+					},
+				},
+			}
+		})
+
+		It("should apply a preferred scsi disk bus to boot and hotplug disks without a bus", func() {
+			preferenceSpec.Devices.PreferredDiskBus = virtv1.DiskBusSCSI
+			vmi.Spec.Domain.Devices.Disks[3].DiskDevice.Disk.Bus = virtv1.DiskBusSATA
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+			disks := vmi.Spec.Domain.Devices.Disks
+			Expect(disks[0].DiskDevice.Disk.Bus).To(Equal(virtv1.DiskBusSCSI))
+			Expect(disks[1].DiskDevice.Disk.Bus).To(Equal(virtv1.DiskBusSCSI))
+			Expect(disks[2].DiskDevice.Disk.Bus).To(Equal(virtv1.DiskBusSCSI))
+			Expect(disks[3].DiskDevice.Disk.Bus).To(Equal(virtv1.DiskBusSATA))
+		})
+
+		It("should only apply a preferred virtio disk bus to the boot disk", func() {
+			Expect(preferenceSpec.Devices.PreferredDiskBus).To(Equal(virtv1.DiskBusVirtio))
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+			disks := vmi.Spec.Domain.Devices.Disks
+			Expect(disks[0].DiskDevice.Disk.Bus).To(Equal(virtv1.DiskBusVirtio))
+			Expect(disks[1].DiskDevice.Disk.Bus).To(BeEmpty())
+			Expect(disks[2].DiskDevice.Disk.Bus).To(BeEmpty())
+			Expect(disks[3].DiskDevice.Disk.Bus).To(Equal(virtv1.DiskBusSCSI))
+		})
+	})
+
+	Context("with a mix of cdrom and disk volumes", func() {
+		BeforeEach(func() {
+			vmi.Spec.Domain.Devices.Disks = []virtv1.Disk{
+				{Name: "containerdisk"},
+				{
+					Name: "installer",
+					DiskDevice: virtv1.DiskDevice{
+						CDRom: &virtv1.CDRomTarget{},
+					},
+				},
+				{
+					Name: "data",
+					DiskDevice: virtv1.DiskDevice{
+						Disk: &virtv1.DiskTarget{
+							Bus: virtv1.DiskBusSATA,
+						},
+					},
+				},
+				{
+					Name: "drivers",
+					DiskDevice: virtv1.DiskDevice{
+						CDRom: &virtv1.CDRomTarget{
+							Bus: virtv1.DiskBusSATA,
+						},
+					},
+				},
+			}
+			vmi.Spec.Volumes = []virtv1.Volume{
+				{
+					Name: "containerdisk",
+					VolumeSource: virtv1.VolumeSource{
+						ContainerDisk: &virtv1.ContainerDiskSource{},
+					},
+				},
+				{
+					Name: "installer",
+					VolumeSource: virtv1.VolumeSource{
+						DataVolume: &virtv1.DataVolumeSource{},
+					},
+				},
+				{
+					Name: "data",
+					VolumeSource: virtv1.VolumeSource{
+						PersistentVolumeClaim: &virtv1.PersistentVolumeClaimVolumeSource{},
+					},
+				},
+				{
+					Name: "drivers",
+					VolumeSource: virtv1.VolumeSource{
+						ContainerDisk: &virtv1.ContainerDiskSource{},
+					},
+				},
+			}
+		})
+
+		It("should apply the preferred bus of each target without a bus", func() {
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+			disks := vmi.Spec.Domain.Devices.Disks
+			Expect(disks[0].DiskDevice.Disk.Bus).To(Equal(preferenceSpec.Devices.PreferredDiskBus))
+			Expect(disks[1].DiskDevice.CDRom.Bus).To(Equal(preferenceSpec.Devices.PreferredCdromBus))
+			Expect(disks[2].DiskDevice.Disk.Bus).To(Equal(virtv1.DiskBusSATA))
+			Expect(disks[3].DiskDevice.CDRom.Bus).To(Equal(virtv1.DiskBusSATA))
+		})
+
+		It("should leave the bus of a target without a preferred bus unset", func() {
+			preferenceSpec.Devices.PreferredCdromBus = ""
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+			disks := vmi.Spec.Domain.Devices.Disks
+			Expect(disks[0].DiskDevice.Disk.Bus).To(Equal(preferenceSpec.Devices.PreferredDiskBus))
+			Expect(disks[1].DiskDevice.CDRom.Bus).To(BeEmpty())
+		})
+	})
+
+	Context("PreferredDiskBlockSize", func() {
+		DescribeTable("should be applied to disks without a block size", func(preferredBlockSize *virtv1.BlockSize) {
+			preferenceSpec.Devices.PreferredDiskBlockSize = preferredBlockSize
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.Disks[0].BlockSize).To(HaveValue(Equal(*userDefinedBlockSize)))
+			Expect(vmi.Spec.Domain.Devices.Disks[1].BlockSize).To(HaveValue(Equal(*preferredBlockSize)))
+			Expect(vmi.Spec.Domain.Devices.Disks[1].BlockSize).ToNot(BeIdenticalTo(preferredBlockSize))
+		},
+			Entry("matching the volume", &virtv1.BlockSize{
+				MatchVolume: &virtv1.FeatureState{Enabled: pointer.P(true)},
+			}),
+			Entry("with explicit logical and physical sizes", &virtv1.BlockSize{
+				Custom: &virtv1.CustomBlockSize{Logical: 512, Physical: 4096},
+			}),
+		)
+	})
+
+	Context("PreferredDiskCache and PreferredDiskIO", func() {
+		DescribeTable("should only fill the unset fields of disks", func(vmiCache virtv1.DriverCache, vmiIO virtv1.DriverIO,
+			expectedCache virtv1.DriverCache, expectedIO virtv1.DriverIO,
+		) {
+			preferenceSpec.Devices.PreferredDiskCache = virtv1.CacheNone
+			preferenceSpec.Devices.PreferredDiskIO = virtv1.IOThreads
+			vmi.Spec.Domain.Devices.Disks[1].Cache = vmiCache
+			vmi.Spec.Domain.Devices.Disks[1].IO = vmiIO
+
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.Disks[1].Cache).To(Equal(expectedCache))
+			Expect(vmi.Spec.Domain.Devices.Disks[1].IO).To(Equal(expectedIO))
+		},
+			Entry("with neither set", virtv1.DriverCache(""), virtv1.DriverIO(""), virtv1.CacheNone, virtv1.IOThreads),
+			Entry("with only cache set", virtv1.CacheWriteBack, virtv1.DriverIO(""), virtv1.CacheWriteBack, virtv1.IOThreads),
+			Entry("with only io set", virtv1.DriverCache(""), virtv1.IONative, virtv1.CacheNone, virtv1.IONative),
+			Entry("with both set", virtv1.CacheWriteThrough, virtv1.IONative, virtv1.CacheWriteThrough, virtv1.IONative),
+		)
+	})
+
+	Context("PreferredUseVirtioTransitional", func() {
+		DescribeTable("should preserve the nil, false and true states", func(vmiValue, preferenceValue, expectedValue *bool) {
+			vmi.Spec.Domain.Devices.UseVirtioTransitional = vmiValue
+			preferenceSpec.Devices.PreferredUseVirtioTransitional = preferenceValue
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.UseVirtioTransitional).To(Equal(expectedValue))
+		},
+			Entry("unset by both", nil, nil, nil),
+			Entry("unset by the VMI and enabled by the preference", nil, pointer.P(true), pointer.P(true)),
+			Entry("unset by the VMI and disabled by the preference", nil, pointer.P(false), pointer.P(false)),
+			Entry("disabled by the VMI and enabled by the preference", pointer.P(false), pointer.P(true), pointer.P(false)),
+			Entry("enabled by the VMI and disabled by the preference", pointer.P(true), pointer.P(false), pointer.P(true)),
+			Entry("enabled by the VMI and unset by the preference", pointer.P(true), nil, pointer.P(true)),
+		)
+	})
+
+	Context("PreferredSoundModel", func() {
+		DescribeTable("should", func(vmiSound, expectedSound *virtv1.SoundDevice) {
+			vmi.Spec.Domain.Devices.Sound = vmiSound
+			preferenceSpec.Devices.PreferredSoundModel = "ich9"
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.Sound).To(Equal(expectedSound))
+		},
+			Entry("apply the model to a sound device without a model",
+				&virtv1.SoundDevice{Name: "audio"},
+				&virtv1.SoundDevice{Name: "audio", Model: "ich9"},
+			),
+			Entry("not apply the model to a sound device providing a model",
+				&virtv1.SoundDevice{Name: "audio", Model: "ac97"},
+				&virtv1.SoundDevice{Name: "audio", Model: "ac97"},
+			),
+			Entry("not attach a sound device when nil within VMI spec",
+				nil,
+				nil,
+			),
+		)
+	})
+
+	Context("PreferredRng", func() {
+		DescribeTable("should",
+			func(vmiRng, preferenceRng, expectedRng *virtv1.Rng) {
+				vmi.Spec.Domain.Devices.Rng = vmiRng
+				preferenceSpec.Devices.PreferredRng = preferenceRng
+				Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+				Expect(vmi.Spec.Domain.Devices.Rng).To(Equal(expectedRng))
+			},
+			Entry("apply when RNG device is nil within VMI spec", nil, &virtv1.Rng{}, &virtv1.Rng{}),
+			Entry("not apply when RNG device is provided within VMI spec", &virtv1.Rng{}, &virtv1.Rng{}, &virtv1.Rng{}),
+			Entry("not add RNG device when not provided by the preference", nil, nil, nil),
+			Entry("keep RNG device provided within VMI spec when not provided by the preference", &virtv1.Rng{}, nil, &virtv1.Rng{}),
+		)
+	})
+
 	Context("PreferredTPM", func() {
 		DescribeTable("should",
 			func(vmiTPM, preferenceTPM, expectedTPM *virtv1.TPMDevice) {
@@ -255,11 +709,26 @@ var _ = Describe("Preference.Devices", func() {
 				Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
 				Expect(vmi.Spec.Domain.Devices.TPM).To(Equal(expectedTPM))
 			},
-			Entry("only apply when TPM device is nil within VMI spec",
+			Entry("apply a persistent TPM device when nil within VMI spec",
 				nil,
 				&virtv1.TPMDevice{Persistent: pointer.P(true)},
 				&virtv1.TPMDevice{Persistent: pointer.P(true)},
 			),
+			Entry("apply an ephemeral TPM device when nil within VMI spec",
+				nil,
+				&virtv1.TPMDevice{Persistent: pointer.P(false)},
+				&virtv1.TPMDevice{Persistent: pointer.P(false)},
+			),
+			Entry("apply a TPM device without persistence when nil within VMI spec",
+				nil,
+				&virtv1.TPMDevice{},
+				&virtv1.TPMDevice{},
+			),
+			Entry("not apply a persistent TPM device when an ephemeral TPM device is provided within VMI spec",
+				&virtv1.TPMDevice{Persistent: pointer.P(false)},
+				&virtv1.TPMDevice{Persistent: pointer.P(true)},
+				&virtv1.TPMDevice{Persistent: pointer.P(false)},
+			),
 			Entry("not apply when TPM device is provided within VMI spec",
 				&virtv1.TPMDevice{Persistent: pointer.P(true)},
 				&virtv1.TPMDevice{},
@@ -276,5 +745,11 @@ var _ = Describe("Preference.Devices", func() {
 				&virtv1.TPMDevice{Enabled: pointer.P(false)},
 			),
 		)
+
+		It("should not share the preferred TPM device with the VMI", func() {
+			preferenceSpec.Devices.PreferredTPM = &virtv1.TPMDevice{Persistent: pointer.P(true)}
+			Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+			Expect(vmi.Spec.Domain.Devices.TPM.Persistent).ToNot(BeIdenticalTo(preferenceSpec.Devices.PreferredTPM.Persistent))
+		})
 	})
 })
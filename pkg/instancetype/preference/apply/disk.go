@@ -27,15 +27,19 @@ import (
 )
 
 func applyDiskPreferences(preferenceSpec *v1beta1.VirtualMachinePreferenceSpec, vmiSpec *virtv1.VirtualMachineInstanceSpec) {
+	hotplugVolumes := getHotplugVolumeNames(vmiSpec)
 	for diskIndex := range vmiSpec.Domain.Devices.Disks {
 		vmiDisk := &vmiSpec.Domain.Devices.Disks[diskIndex]
+		_, isHotplugDisk := hotplugVolumes[vmiDisk.Name]
 		// If we don't have a target device defined default to a DiskTarget so we can apply preferences
 		if vmiDisk.DiskDevice.Disk == nil && vmiDisk.DiskDevice.CDRom == nil && vmiDisk.DiskDevice.LUN == nil {
 			vmiDisk.DiskDevice.Disk = &virtv1.DiskTarget{}
 		}
 
 		if vmiDisk.DiskDevice.Disk != nil {
-			if preferenceSpec.Devices.PreferredDiskBus != "" && vmiDisk.DiskDevice.Disk.Bus == "" {
+			// Hotplugged disks only support the scsi bus so any other preferred bus is not applied to them
+			if preferenceSpec.Devices.PreferredDiskBus != "" && vmiDisk.DiskDevice.Disk.Bus == "" &&
+				(!isHotplugDisk || preferenceSpec.Devices.PreferredDiskBus == virtv1.DiskBusSCSI) {
 				vmiDisk.DiskDevice.Disk.Bus = preferenceSpec.Devices.PreferredDiskBus
 			}
 
@@ -67,3 +71,16 @@ func applyDiskPreferences(preferenceSpec *v1beta1.VirtualMachinePreferenceSpec,
 		}
 	}
 }
+
+// getHotplugVolumeNames returns the names of all volumes that can be hotplugged to and from the VMI.
+// Disks backed by these volumes only receive a preferred disk bus of scsi, the only bus supported when hotplugging.
+func getHotplugVolumeNames(vmiSpec *virtv1.VirtualMachineInstanceSpec) map[string]struct{} {
+	hotplugVolumes := map[string]struct{}{}
+	for _, volume := range vmiSpec.Volumes {
+		if (volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.Hotpluggable) ||
+			(volume.DataVolume != nil && volume.DataVolume.Hotpluggable) {
+			hotplugVolumes[volume.Name] = struct{}{}
+		}
+	}
+	return hotplugVolumes
+}
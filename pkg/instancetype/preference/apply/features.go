@@ -33,8 +33,8 @@ func applyFeaturePreferences(preferenceSpec *v1beta1.VirtualMachinePreferenceSpe
 		vmiSpec.Domain.Features = &virtv1.Features{}
 	}
 
-	// FIXME vmiSpec.Domain.Features.ACPI isn't a FeatureState pointer so just overwrite if we have a preference for now.
-	if preferenceSpec.Features.PreferredAcpi != nil {
+	// vmiSpec.Domain.Features.ACPI isn't a FeatureState pointer so treat an unset Enabled as ACPI not being provided by the VMI
+	if preferenceSpec.Features.PreferredAcpi != nil && vmiSpec.Domain.Features.ACPI.Enabled == nil {
 		vmiSpec.Domain.Features.ACPI = *preferenceSpec.Features.PreferredAcpi.DeepCopy()
 	}
 
@@ -92,4 +92,49 @@ var _ = Describe("Preference.Features", func() {
 		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
 		Expect(vmi.Spec.Domain.Features.Hyperv.EVMCS.Enabled).To(HaveValue(BeFalse()))
 	})
+
+	It("should only fill features not already defined in the VMI", func() {
+		vmi.Spec.Domain.Features = &virtv1.Features{
+			ACPI: virtv1.FeatureState{
+				Enabled: pointer.P(false),
+			},
+			SMM: &virtv1.FeatureState{
+				Enabled: pointer.P(false),
+			},
+			Hyperv: &virtv1.FeatureHyperv{
+				Relaxed: &virtv1.FeatureState{
+					Enabled: pointer.P(false),
+				},
+				Spinlocks: &virtv1.FeatureSpinlocks{
+					Enabled: pointer.P(false),
+				},
+			},
+		}
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+		// Features provided by the VMI are kept
+		Expect(vmi.Spec.Domain.Features.ACPI.Enabled).To(HaveValue(BeFalse()))
+		Expect(vmi.Spec.Domain.Features.SMM.Enabled).To(HaveValue(BeFalse()))
+		Expect(vmi.Spec.Domain.Features.Hyperv.Relaxed.Enabled).To(HaveValue(BeFalse()))
+		Expect(vmi.Spec.Domain.Features.Hyperv.Spinlocks).To(HaveValue(Equal(virtv1.FeatureSpinlocks{Enabled: pointer.P(false)})))
+
+		// The remaining features are supplied by the preference
+		Expect(vmi.Spec.Domain.Features.APIC).To(HaveValue(Equal(*preferenceSpec.Features.PreferredApic)))
+		Expect(vmi.Spec.Domain.Features.KVM).To(HaveValue(Equal(*preferenceSpec.Features.PreferredKvm)))
+		Expect(vmi.Spec.Domain.Features.Pvspinlock).To(HaveValue(Equal(*preferenceSpec.Features.PreferredPvspinlock)))
+		Expect(vmi.Spec.Domain.Features.Hyperv.VAPIC).To(HaveValue(Equal(*preferenceSpec.Features.PreferredHyperv.VAPIC)))
+		Expect(vmi.Spec.Domain.Features.Hyperv.VendorID).To(HaveValue(Equal(*preferenceSpec.Features.PreferredHyperv.VendorID)))
+		Expect(vmi.Spec.Domain.Features.Hyperv.SyNICTimer).To(HaveValue(Equal(*preferenceSpec.Features.PreferredHyperv.SyNICTimer)))
+	})
+
+	It("should apply ACPI when the VMI provides an empty ACPI feature state", func() {
+		preferenceSpec.Features.PreferredAcpi = &virtv1.FeatureState{
+			Enabled: pointer.P(true),
+		}
+		vmi.Spec.Domain.Features = &virtv1.Features{}
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Domain.Features.ACPI.Enabled).To(HaveValue(BeTrue()))
+	})
 })
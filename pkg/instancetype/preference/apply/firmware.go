@@ -55,10 +55,13 @@ func applyFirmwarePreferences(preferenceSpec *v1beta1.VirtualMachinePreferenceSp
 
 	if vmiFirmware.Bootloader.EFI == nil && vmiFirmware.Bootloader.BIOS == nil && firmware.PreferredEfi != nil {
 		vmiFirmware.Bootloader.EFI = firmware.PreferredEfi.DeepCopy()
+		applySecureBootSMM(vmiSpec)
 		// When using PreferredEfi return early to avoid applying DeprecatedPreferredUseEfi or DeprecatedPreferredUseSecureBoot below
 		return
 	}
 
+	efiProvidedByVMI := vmiFirmware.Bootloader.EFI != nil
+
 	if firmware.DeprecatedPreferredUseEfi != nil &&
 		*firmware.DeprecatedPreferredUseEfi &&
 		vmiFirmware.Bootloader.EFI == nil &&
@@ -69,4 +72,30 @@ func applyFirmwarePreferences(preferenceSpec *v1beta1.VirtualMachinePreferenceSp
 	if firmware.DeprecatedPreferredUseSecureBoot != nil && vmiFirmware.Bootloader.EFI != nil && vmiFirmware.Bootloader.EFI.SecureBoot == nil {
 		vmiFirmware.Bootloader.EFI.SecureBoot = pointer.P(*firmware.DeprecatedPreferredUseSecureBoot)
 	}
+
+	if !efiProvidedByVMI && vmiFirmware.Bootloader.EFI != nil {
+		applySecureBootSMM(vmiSpec)
+	}
+}
+
+// IsSecureBootEnabled returns true when EFI is used with SecureBoot either enabled or left to its default of being enabled
+func IsSecureBootEnabled(vmiSpec *virtv1.VirtualMachineInstanceSpec) bool {
+	firmware := vmiSpec.Domain.Firmware
+	return firmware != nil && firmware.Bootloader != nil && firmware.Bootloader.EFI != nil &&
+		(firmware.Bootloader.EFI.SecureBoot == nil || *firmware.Bootloader.EFI.SecureBoot)
+}
+
+// applySecureBootSMM enables SMM, required by SecureBoot, when EFI has been applied from the preference and SMM has not been provided
+func applySecureBootSMM(vmiSpec *virtv1.VirtualMachineInstanceSpec) {
+	if !IsSecureBootEnabled(vmiSpec) {
+		return
+	}
+	if vmiSpec.Domain.Features == nil {
+		vmiSpec.Domain.Features = &virtv1.Features{}
+	}
+	if vmiSpec.Domain.Features.SMM == nil {
+		vmiSpec.Domain.Features.SMM = &virtv1.FeatureState{
+			Enabled: pointer.P(true),
+		}
+	}
 }
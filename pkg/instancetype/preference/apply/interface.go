@@ -38,10 +38,17 @@ func isInterfaceOnPodNetwork(interfaceName string, vmiSpec *virtv1.VirtualMachin
 	return false
 }
 
+// isInterfaceModelEligible returns true when the preferred interface model can be applied to the interface.
+// Interfaces without a binding or using the bridge, masquerade or any other binding including plugins are eligible while
+// SR-IOV interfaces are skipped as the model of a passed through VF is meaningless.
+func isInterfaceModelEligible(iface *virtv1.Interface) bool {
+	return iface.SRIOV == nil
+}
+
 func applyInterfacePreferences(preferenceSpec *v1beta1.VirtualMachinePreferenceSpec, vmiSpec *virtv1.VirtualMachineInstanceSpec) {
 	for ifaceIndex := range vmiSpec.Domain.Devices.Interfaces {
 		vmiIface := &vmiSpec.Domain.Devices.Interfaces[ifaceIndex]
-		if preferenceSpec.Devices.PreferredInterfaceModel != "" && vmiIface.Model == "" {
+		if preferenceSpec.Devices.PreferredInterfaceModel != "" && vmiIface.Model == "" && isInterfaceModelEligible(vmiIface) {
 			vmiIface.Model = preferenceSpec.Devices.PreferredInterfaceModel
 		}
 		if preferenceSpec.Devices.PreferredInterfaceMasquerade != nil &&
@@ -38,4 +38,29 @@ var _ = Describe("Preference.Machine", func() {
 
 		Expect(vmi.Spec.Domain.Machine.Type).To(Equal(preferenceSpec.Machine.PreferredMachineType))
 	})
+
+	It("should not overwrite a machine type provided by the VMI", func() {
+		vmi.Spec.Domain.Machine = &virtv1.Machine{
+			Type: "pc-q35-rhel9.4.0",
+		}
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			Machine: &v1beta1.MachinePreferences{
+				PreferredMachineType: "q35-rhel-8.0",
+			},
+		}
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+		Expect(vmi.Spec.Domain.Machine.Type).To(Equal("pc-q35-rhel9.4.0"))
+	})
+
+	It("should not create a machine when no machine type is preferred", func() {
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			Machine: &v1beta1.MachinePreferences{},
+		}
+
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+
+		Expect(vmi.Spec.Domain.Machine).To(BeNil())
+	})
 })
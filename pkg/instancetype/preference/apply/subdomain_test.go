@@ -36,6 +36,15 @@ var _ = Describe("Preference.PreferredSubdomain", func() {
 		Expect(vmi.Spec.Subdomain).To(Equal(*preferenceSpec.PreferredSubdomain))
 	})
 
+	It("should apply a DNS label to a VMI with an empty subdomain", func() {
+		vmi.Spec.Subdomain = ""
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{
+			PreferredSubdomain: pointer.P("my-subdomain"),
+		}
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.Subdomain).To(Equal("my-subdomain"))
+	})
+
 	It("should not overwrite user defined value", func() {
 		const userDefinedValue = "foo.com"
 		vmi.Spec.Subdomain = userDefinedValue
@@ -47,4 +47,10 @@ var _ = Describe("Preference.PreferredTerminationGracePeriodSeconds", func() {
 		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
 		Expect(vmi.Spec.TerminationGracePeriodSeconds).To(HaveValue(Equal(userDefinedValue)))
 	})
+
+	It("should leave the VMI value unset when not provided by the preference", func() {
+		preferenceSpec = &v1beta1.VirtualMachinePreferenceSpec{}
+		Expect(vmiApplier.ApplyToVMI(field, instancetypeSpec, preferenceSpec, &vmi.Spec, &vmi.ObjectMeta)).To(Succeed())
+		Expect(vmi.Spec.TerminationGracePeriodSeconds).To(BeNil())
+	})
 })
@@ -51,6 +51,7 @@ const (
 	spreadAcrossCoresThreadsErrFmt        = "%d vCPUs provided by the instance type are not divisible by the number of threads per core %d"
 	spreadAcrossSocketsCoresThreadsErrFmt = "%d vCPUs provided by the instance type are not divisible by the number of threads per core " +
 		"%d and Spec.PreferSpreadSocketToCoreRatio or Spec.CPU.PreferSpreadOptions.Ratio of %d"
+	spreadRatioZeroErr = "a Spec.CPU.PreferSpreadOptions.Ratio of 0 provided by the preference can not be used to spread vCPUs"
 )
 
 func CheckSpreadCPUTopology(
@@ -63,6 +64,9 @@ func CheckSpreadCPUTopology(
 	}
 
 	ratio, across := apply.GetSpreadOptions(preferenceSpec)
+	if ratio == 0 {
+		return conflict.NewWithMessage(spreadRatioZeroErr, instancetypeCPUGuestPath)
+	}
 	switch across {
 	case v1beta1.SpreadAcrossSocketsCores:
 		if (instancetypeSpec.CPU.Guest % ratio) > 0 {
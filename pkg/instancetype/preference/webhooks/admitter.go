@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 
+	virtv1 "kubevirt.io/api/core/v1"
 	instancetypeapi "kubevirt.io/api/instancetype"
 	instancetypeapiv1beta1 "kubevirt.io/api/instancetype/v1beta1"
 
@@ -35,6 +38,14 @@ func ValidatePreferenceSpec(field *k8sfield.Path, spec *instancetypeapiv1beta1.V
 
 	causes = append(causes, validatePreferredCPUTopology(field, spec)...)
 	causes = append(causes, validateSpreadOptions(field, spec)...)
+	causes = append(causes, validatePreferredMachineType(field, spec)...)
+	causes = append(causes, validatePreferredTerminationGracePeriodSeconds(field, spec)...)
+	causes = append(causes, validatePreferredSubdomain(field, spec)...)
+	causes = append(causes, validatePreferredSoundModel(field, spec)...)
+	causes = append(causes, validatePreferredDiskBlockSize(field, spec)...)
+	causes = append(causes, validatePreferredDiskCache(field, spec)...)
+	causes = append(causes, validatePreferredDiskIO(field, spec)...)
+	causes = append(causes, validatePreferredCPUFeatures(field, spec)...)
 	return causes
 }
 
@@ -57,6 +68,7 @@ func validatePreferredCPUTopology(field *k8sfield.Path, spec *instancetypeapiv1b
 
 const (
 	spreadAcrossCoresThreadsRatioErr = "only a ratio of 2 (1 core 2 threads) is allowed when spreading vCPUs over cores and threads"
+	spreadRatioZeroErr               = "a ratio of 0 can not be used to spread vCPUs"
 	spreadAcrossUnsupportedErrFmt    = "across %s is not supported"
 )
 
@@ -87,6 +99,15 @@ func validateSpreadOptions(field *k8sfield.Path, spec *instancetypeapiv1beta1.Vi
 		}}
 	}
 
+	// A ratio of 0 can only be provided through spreadOptions as preferSpreadSocketToCoreRatio uses 0 to select the default
+	if ratio == 0 {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: spreadRatioZeroErr,
+			Field:   field.Child("cpu", "spreadOptions", "ratio").String(),
+		}}
+	}
+
 	if across == instancetypeapiv1beta1.SpreadAcrossCoresThreads && ratio != 2 {
 		return []metav1.StatusCause{{
 			Type:    metav1.CauseTypeFieldValueInvalid,
@@ -97,6 +118,165 @@ func validateSpreadOptions(field *k8sfield.Path, spec *instancetypeapiv1beta1.Vi
 	return nil
 }
 
+const preferredMachineTypeBlankErr = "preferredMachineType must not only contain whitespace"
+
+// validatePreferredMachineType only rejects an explicitly provided blank value as preferredMachineType is omitempty,
+// leaving an empty string indistinguishable from the field not being provided at all.
+func validatePreferredMachineType(field *k8sfield.Path, spec *instancetypeapiv1beta1.VirtualMachinePreferenceSpec) []metav1.StatusCause {
+	if spec.Machine == nil || spec.Machine.PreferredMachineType == "" || strings.TrimSpace(spec.Machine.PreferredMachineType) != "" {
+		return nil
+	}
+	return []metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldValueInvalid,
+		Message: preferredMachineTypeBlankErr,
+		Field:   field.Child("machine", "preferredMachineType").String(),
+	}}
+}
+
+const preferredTerminationGracePeriodSecondsNegativeErrFmt = "preferredTerminationGracePeriodSeconds %d must not be negative"
+
+func validatePreferredTerminationGracePeriodSeconds(
+	field *k8sfield.Path,
+	spec *instancetypeapiv1beta1.VirtualMachinePreferenceSpec,
+) []metav1.StatusCause {
+	if spec.PreferredTerminationGracePeriodSeconds == nil || *spec.PreferredTerminationGracePeriodSeconds >= 0 {
+		return nil
+	}
+	return []metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldValueInvalid,
+		Message: fmt.Sprintf(preferredTerminationGracePeriodSecondsNegativeErrFmt, *spec.PreferredTerminationGracePeriodSeconds),
+		Field:   field.Child("preferredTerminationGracePeriodSeconds").String(),
+	}}
+}
+
+const preferredSubdomainInvalidErrFmt = "preferredSubdomain %s is not a valid DNS label: %s"
+
+func validatePreferredSubdomain(field *k8sfield.Path, spec *instancetypeapiv1beta1.VirtualMachinePreferenceSpec) []metav1.StatusCause {
+	if spec.PreferredSubdomain == nil {
+		return nil
+	}
+	errs := k8svalidation.IsDNS1123Label(*spec.PreferredSubdomain)
+	if len(errs) == 0 {
+		return nil
+	}
+	return []metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldValueInvalid,
+		Message: fmt.Sprintf(preferredSubdomainInvalidErrFmt, *spec.PreferredSubdomain, strings.Join(errs, ", ")),
+		Field:   field.Child("preferredSubdomain").String(),
+	}}
+}
+
+const preferredSoundModelUnsupportedErrFmt = "preferredSoundModel %s is not supported, supported models are %s"
+
+var supportedSoundModels = []string{"ac97", "ich9"}
+
+func validatePreferredSoundModel(field *k8sfield.Path, spec *instancetypeapiv1beta1.VirtualMachinePreferenceSpec) []metav1.StatusCause {
+	if spec.Devices == nil || spec.Devices.PreferredSoundModel == "" ||
+		slices.Contains(supportedSoundModels, spec.Devices.PreferredSoundModel) {
+		return nil
+	}
+	return []metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldValueNotSupported,
+		Message: fmt.Sprintf(preferredSoundModelUnsupportedErrFmt, spec.Devices.PreferredSoundModel, strings.Join(supportedSoundModels, ", ")),
+		Field:   field.Child("devices", "preferredSoundModel").String(),
+	}}
+}
+
+const (
+	preferredDiskBlockSizeMatchVolumeWithCustomErr = "preferredDiskBlockSize can't enable matchVolume together with a custom block size"
+	preferredDiskBlockSizeLogicalGreaterErrFmt     = "preferredDiskBlockSize logical size %d must be the same or less than " +
+		"the physical size of %d"
+)
+
+func validatePreferredDiskBlockSize(field *k8sfield.Path, spec *instancetypeapiv1beta1.VirtualMachinePreferenceSpec) []metav1.StatusCause {
+	if spec.Devices == nil || spec.Devices.PreferredDiskBlockSize == nil || spec.Devices.PreferredDiskBlockSize.Custom == nil {
+		return nil
+	}
+	blockSize := spec.Devices.PreferredDiskBlockSize
+	blockSizeField := field.Child("devices", "preferredDiskBlockSize")
+	if blockSize.MatchVolume != nil && (blockSize.MatchVolume.Enabled == nil || *blockSize.MatchVolume.Enabled) {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: preferredDiskBlockSizeMatchVolumeWithCustomErr,
+			Field:   blockSizeField.String(),
+		}}
+	}
+	if blockSize.Custom.Logical > blockSize.Custom.Physical {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf(preferredDiskBlockSizeLogicalGreaterErrFmt, blockSize.Custom.Logical, blockSize.Custom.Physical),
+			Field:   blockSizeField.Child("custom", "logical").String(),
+		}}
+	}
+	return nil
+}
+
+const (
+	preferredDiskCacheUnsupportedErrFmt = "preferredDiskCache %s is not supported, supported modes are %s"
+	preferredDiskIOUnsupportedErrFmt    = "preferredDiskIO %s is not supported, supported modes are %s"
+)
+
+var (
+	supportedDiskCaches = []virtv1.DriverCache{virtv1.CacheNone, virtv1.CacheWriteBack, virtv1.CacheWriteThrough}
+	supportedDiskIOs    = []virtv1.DriverIO{virtv1.IONative, virtv1.IOThreads}
+)
+
+func validatePreferredDiskCache(field *k8sfield.Path, spec *instancetypeapiv1beta1.VirtualMachinePreferenceSpec) []metav1.StatusCause {
+	if spec.Devices == nil || spec.Devices.PreferredDiskCache == "" || slices.Contains(supportedDiskCaches, spec.Devices.PreferredDiskCache) {
+		return nil
+	}
+	return []metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldValueNotSupported,
+		Message: fmt.Sprintf(preferredDiskCacheUnsupportedErrFmt, spec.Devices.PreferredDiskCache, joinValues(supportedDiskCaches)),
+		Field:   field.Child("devices", "preferredDiskCache").String(),
+	}}
+}
+
+func validatePreferredDiskIO(field *k8sfield.Path, spec *instancetypeapiv1beta1.VirtualMachinePreferenceSpec) []metav1.StatusCause {
+	if spec.Devices == nil || spec.Devices.PreferredDiskIO == "" || slices.Contains(supportedDiskIOs, spec.Devices.PreferredDiskIO) {
+		return nil
+	}
+	return []metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldValueNotSupported,
+		Message: fmt.Sprintf(preferredDiskIOUnsupportedErrFmt, spec.Devices.PreferredDiskIO, joinValues(supportedDiskIOs)),
+		Field:   field.Child("devices", "preferredDiskIO").String(),
+	}}
+}
+
+func joinValues[T ~string](values []T) string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		strs = append(strs, string(value))
+	}
+	return strings.Join(strs, ", ")
+}
+
+const preferredCPUFeaturePolicyConflictErrFmt = "preferredCPUFeatures policy %s of CPU feature %s conflicts with policy %s " +
+	"provided earlier for the same CPU feature"
+
+func validatePreferredCPUFeatures(field *k8sfield.Path, spec *instancetypeapiv1beta1.VirtualMachinePreferenceSpec) []metav1.StatusCause {
+	if spec.CPU == nil {
+		return nil
+	}
+	var causes []metav1.StatusCause
+	policies := make(map[string]string)
+	for idx, feature := range spec.CPU.PreferredCPUFeatures {
+		policy, found := policies[feature.Name]
+		if !found {
+			policies[feature.Name] = feature.Policy
+			continue
+		}
+		if policy != feature.Policy {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf(preferredCPUFeaturePolicyConflictErrFmt, feature.Policy, feature.Name, policy),
+				Field:   field.Child("cpu", "preferredCPUFeatures").Index(idx).Child("policy").String(),
+			})
+		}
+	}
+	return causes
+}
+
 const deprecatedPreferredCPUTopologyErrFmt = "PreferredCPUTopology %s is deprecated for removal in a future release, please use %s instead"
 
 var deprecatedTopologies = map[instancetypeapiv1beta1.PreferredCPUTopology]instancetypeapiv1beta1.PreferredCPUTopology{
@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 
+	virtv1 "kubevirt.io/api/core/v1"
 	apiinstancetype "kubevirt.io/api/instancetype"
 	instancetypev1beta1 "kubevirt.io/api/instancetype/v1beta1"
 
@@ -83,6 +84,30 @@ var _ = Describe("Validating Preference Admitter", func() {
 		Entry("with preferSpread", instancetypev1beta1.DeprecatedPreferSpread),
 	)
 
+	DescribeTable("should reject a spread ratio of 0", func(preferredCPUTopology instancetypev1beta1.PreferredCPUTopology) {
+		preferenceObj = &instancetypev1beta1.VirtualMachinePreference{
+			Spec: instancetypev1beta1.VirtualMachinePreferenceSpec{
+				CPU: &instancetypev1beta1.CPUPreferences{
+					PreferredCPUTopology: &preferredCPUTopology,
+					SpreadOptions: &instancetypev1beta1.SpreadOptions{
+						Ratio: pointer.P(uint32(0)),
+					},
+				},
+			},
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeFalse(), "Expected preference to not be allowed")
+		Expect(response.Result.Details.Causes).To(HaveLen(1))
+		Expect(response.Result.Details.Causes[0].Type).To(Equal(metav1.CauseTypeFieldValueInvalid))
+		Expect(response.Result.Details.Causes[0].Message).To(Equal("a ratio of 0 can not be used to spread vCPUs"))
+		Expect(response.Result.Details.Causes[0].Field).To(Equal(k8sfield.NewPath("spec", "cpu", "spreadOptions", "ratio").String()))
+	},
+		Entry("with spread", instancetypev1beta1.Spread),
+		Entry("with preferSpread", instancetypev1beta1.DeprecatedPreferSpread),
+	)
+
 	DescribeTable("should reject when spreading vCPUs across CoresThreads with a ratio higher than 2 set through",
 		func(preferenceObj instancetypev1beta1.VirtualMachinePreference) {
 			ar := createPreferenceAdmissionReview(&preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
@@ -185,6 +210,272 @@ var _ = Describe("Validating Preference Admitter", func() {
 			instancetypev1beta1.Any,
 		),
 	)
+
+	DescribeTable("should reject a blank PreferredMachineType", func(machineType string) {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			Machine: &instancetypev1beta1.MachinePreferences{
+				PreferredMachineType: machineType,
+			},
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeFalse(), "Expected preference to not be allowed")
+		Expect(response.Result.Details.Causes).To(HaveLen(1))
+		Expect(response.Result.Details.Causes[0].Type).To(Equal(metav1.CauseTypeFieldValueInvalid))
+		Expect(response.Result.Details.Causes[0].Message).To(Equal(
+			"preferredMachineType must not only contain whitespace"))
+		Expect(response.Result.Details.Causes[0].Field).To(Equal(k8sfield.NewPath("spec", "machine", "preferredMachineType").String()))
+	},
+		Entry("when only spaces", "  "),
+		Entry("when only a tab", "\t"),
+	)
+
+	It("should reject a negative PreferredTerminationGracePeriodSeconds", func() {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			PreferredTerminationGracePeriodSeconds: pointer.P(int64(-1)),
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeFalse(), "Expected preference to not be allowed")
+		Expect(response.Result.Details.Causes).To(HaveLen(1))
+		Expect(response.Result.Details.Causes[0].Type).To(Equal(metav1.CauseTypeFieldValueInvalid))
+		Expect(response.Result.Details.Causes[0].Message).To(Equal("preferredTerminationGracePeriodSeconds -1 must not be negative"))
+		Expect(response.Result.Details.Causes[0].Field).To(Equal(
+			k8sfield.NewPath("spec", "preferredTerminationGracePeriodSeconds").String()))
+	})
+
+	DescribeTable("should accept PreferredTerminationGracePeriodSeconds", func(seconds int64) {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			PreferredTerminationGracePeriodSeconds: pointer.P(seconds),
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeTrue(), "Expected preference to be allowed")
+	},
+		Entry("of zero", int64(0)),
+		Entry("greater than zero", int64(180)),
+	)
+
+	DescribeTable("should reject an invalid PreferredSubdomain", func(subdomain string) {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			PreferredSubdomain: pointer.P(subdomain),
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeFalse(), "Expected preference to not be allowed")
+		Expect(response.Result.Details.Causes).To(HaveLen(1))
+		Expect(response.Result.Details.Causes[0].Type).To(Equal(metav1.CauseTypeFieldValueInvalid))
+		Expect(response.Result.Details.Causes[0].Message).To(HavePrefix(
+			fmt.Sprintf("preferredSubdomain %s is not a valid DNS label: ", subdomain)))
+		Expect(response.Result.Details.Causes[0].Field).To(Equal(k8sfield.NewPath("spec", "preferredSubdomain").String()))
+	},
+		Entry("that is empty", ""),
+		Entry("containing a dot", "kubevirt.io"),
+		Entry("containing uppercase characters", "Subdomain"),
+		Entry("ending with a hyphen", "subdomain-"),
+	)
+
+	It("should accept a valid PreferredSubdomain", func() {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			PreferredSubdomain: pointer.P("my-subdomain"),
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeTrue(), "Expected preference to be allowed")
+	})
+
+	It("should reject an unsupported PreferredSoundModel", func() {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			Devices: &instancetypev1beta1.DevicePreferences{
+				PreferredSoundModel: "sb16",
+			},
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeFalse(), "Expected preference to not be allowed")
+		Expect(response.Result.Details.Causes).To(HaveLen(1))
+		Expect(response.Result.Details.Causes[0].Type).To(Equal(metav1.CauseTypeFieldValueNotSupported))
+		Expect(response.Result.Details.Causes[0].Message).To(Equal(
+			"preferredSoundModel sb16 is not supported, supported models are ac97, ich9"))
+		Expect(response.Result.Details.Causes[0].Field).To(Equal(k8sfield.NewPath("spec", "devices", "preferredSoundModel").String()))
+	})
+
+	DescribeTable("should accept a supported PreferredSoundModel", func(model string) {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			Devices: &instancetypev1beta1.DevicePreferences{
+				PreferredSoundModel: model,
+			},
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeTrue(), "Expected preference to be allowed")
+	},
+		Entry("of ich9", "ich9"),
+		Entry("of ac97", "ac97"),
+	)
+
+	DescribeTable("should reject an invalid PreferredDiskBlockSize", func(
+		blockSize *virtv1.BlockSize, expectedMessage string, expectedField *k8sfield.Path,
+	) {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			Devices: &instancetypev1beta1.DevicePreferences{
+				PreferredDiskBlockSize: blockSize,
+			},
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeFalse(), "Expected preference to not be allowed")
+		Expect(response.Result.Details.Causes).To(HaveLen(1))
+		Expect(response.Result.Details.Causes[0].Type).To(Equal(metav1.CauseTypeFieldValueInvalid))
+		Expect(response.Result.Details.Causes[0].Message).To(Equal(expectedMessage))
+		Expect(response.Result.Details.Causes[0].Field).To(Equal(expectedField.String()))
+	},
+		Entry("with a logical size greater than the physical size",
+			&virtv1.BlockSize{Custom: &virtv1.CustomBlockSize{Logical: 4096, Physical: 512}},
+			"preferredDiskBlockSize logical size 4096 must be the same or less than the physical size of 512",
+			k8sfield.NewPath("spec", "devices", "preferredDiskBlockSize", "custom", "logical"),
+		),
+		Entry("with matchVolume enabled together with a custom size",
+			&virtv1.BlockSize{
+				Custom:      &virtv1.CustomBlockSize{Logical: 512, Physical: 512},
+				MatchVolume: &virtv1.FeatureState{},
+			},
+			"preferredDiskBlockSize can't enable matchVolume together with a custom block size",
+			k8sfield.NewPath("spec", "devices", "preferredDiskBlockSize"),
+		),
+	)
+
+	DescribeTable("should accept a valid PreferredDiskBlockSize", func(blockSize *virtv1.BlockSize) {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			Devices: &instancetypev1beta1.DevicePreferences{
+				PreferredDiskBlockSize: blockSize,
+			},
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeTrue(), "Expected preference to be allowed")
+	},
+		Entry("matching the volume", &virtv1.BlockSize{MatchVolume: &virtv1.FeatureState{}}),
+		Entry("with equal logical and physical sizes", &virtv1.BlockSize{Custom: &virtv1.CustomBlockSize{Logical: 512, Physical: 512}}),
+		Entry("with a logical size less than the physical size",
+			&virtv1.BlockSize{Custom: &virtv1.CustomBlockSize{Logical: 512, Physical: 4096}},
+		),
+		Entry("with a custom size and matchVolume disabled", &virtv1.BlockSize{
+			Custom:      &virtv1.CustomBlockSize{Logical: 512, Physical: 4096},
+			MatchVolume: &virtv1.FeatureState{Enabled: pointer.P(false)},
+		}),
+	)
+
+	DescribeTable("should reject an unsupported", func(
+		devices *instancetypev1beta1.DevicePreferences, expectedMessage string, expectedField *k8sfield.Path,
+	) {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			Devices: devices,
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeFalse(), "Expected preference to not be allowed")
+		Expect(response.Result.Details.Causes).To(HaveLen(1))
+		Expect(response.Result.Details.Causes[0].Type).To(Equal(metav1.CauseTypeFieldValueNotSupported))
+		Expect(response.Result.Details.Causes[0].Message).To(Equal(expectedMessage))
+		Expect(response.Result.Details.Causes[0].Field).To(Equal(expectedField.String()))
+	},
+		Entry("PreferredDiskCache",
+			&instancetypev1beta1.DevicePreferences{PreferredDiskCache: "unsafe"},
+			"preferredDiskCache unsafe is not supported, supported modes are none, writeback, writethrough",
+			k8sfield.NewPath("spec", "devices", "preferredDiskCache"),
+		),
+		Entry("PreferredDiskIO",
+			&instancetypev1beta1.DevicePreferences{PreferredDiskIO: "io_uring"},
+			"preferredDiskIO io_uring is not supported, supported modes are native, threads",
+			k8sfield.NewPath("spec", "devices", "preferredDiskIO"),
+		),
+	)
+
+	DescribeTable("should accept a supported", func(devices *instancetypev1beta1.DevicePreferences) {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			Devices: devices,
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeTrue(), "Expected preference to be allowed")
+	},
+		Entry("PreferredDiskCache of none", &instancetypev1beta1.DevicePreferences{PreferredDiskCache: virtv1.CacheNone}),
+		Entry("PreferredDiskCache of writeback", &instancetypev1beta1.DevicePreferences{PreferredDiskCache: virtv1.CacheWriteBack}),
+		Entry("PreferredDiskCache of writethrough", &instancetypev1beta1.DevicePreferences{PreferredDiskCache: virtv1.CacheWriteThrough}),
+		Entry("PreferredDiskIO of native", &instancetypev1beta1.DevicePreferences{PreferredDiskIO: virtv1.IONative}),
+		Entry("PreferredDiskIO of threads", &instancetypev1beta1.DevicePreferences{PreferredDiskIO: virtv1.IOThreads}),
+	)
+
+	It("should reject conflicting policies of the same PreferredCPUFeature", func() {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			CPU: &instancetypev1beta1.CPUPreferences{
+				PreferredCPUFeatures: []virtv1.CPUFeature{
+					{Name: "vmx", Policy: "require"},
+					{Name: "svm", Policy: "disable"},
+					{Name: "vmx", Policy: "disable"},
+				},
+			},
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeFalse(), "Expected preference to not be allowed")
+		Expect(response.Result.Details.Causes).To(HaveLen(1))
+		Expect(response.Result.Details.Causes[0].Type).To(Equal(metav1.CauseTypeFieldValueInvalid))
+		Expect(response.Result.Details.Causes[0].Message).To(Equal(
+			"preferredCPUFeatures policy disable of CPU feature vmx conflicts with policy require provided earlier for the same CPU feature"))
+		Expect(response.Result.Details.Causes[0].Field).To(Equal(
+			k8sfield.NewPath("spec", "cpu", "preferredCPUFeatures").Index(2).Child("policy").String()))
+	})
+
+	It("should accept the same PreferredCPUFeature repeated with the same policy", func() {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			CPU: &instancetypev1beta1.CPUPreferences{
+				PreferredCPUFeatures: []virtv1.CPUFeature{
+					{Name: "vmx", Policy: "require"},
+					{Name: "vmx", Policy: "require"},
+				},
+			},
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeTrue(), "Expected preference to be allowed")
+	})
+
+	It("should accept a PreferredMachineType", func() {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			Machine: &instancetypev1beta1.MachinePreferences{
+				PreferredMachineType: "q35",
+			},
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeTrue(), "Expected preference to be allowed")
+	})
+
+	It("should accept machine preferences without a PreferredMachineType", func() {
+		preferenceObj.Spec = instancetypev1beta1.VirtualMachinePreferenceSpec{
+			Machine: &instancetypev1beta1.MachinePreferences{},
+		}
+		ar := createPreferenceAdmissionReview(preferenceObj, instancetypev1beta1.SchemeGroupVersion.Version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeTrue(), "Expected preference to be allowed")
+	})
 })
 
 var _ = Describe("Validating ClusterPreference Admitter", func() {
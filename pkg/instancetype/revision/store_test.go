@@ -263,7 +263,7 @@ var _ = Describe("Instancetype and Preferences revision handler", func() {
 					Cores: 1,
 				}
 				Expect(storeHandler.Store(vm)).To(MatchError(
-					conflict.Conflicts{conflict.New("spec", "template", "spec", "domain", "cpu", "cores")}))
+					conflict.Conflicts{conflict.New("spec", "template", "spec", "domain", "cpu", "cores")}.WithSource(conflict.SourceInstancetype)))
 			})
 			It("store InferFromVolumeFailurePolicy when missing from InstancetypeRef", func() {
 				vm.Spec.Instancetype.InferFromVolumeFailurePolicy = pointer.P(virtv1.IgnoreInferFromVolumeFailure)
@@ -391,7 +391,8 @@ var _ = Describe("Instancetype and Preferences revision handler", func() {
 				vm.Spec.Template.Spec.Domain.CPU = &virtv1.CPU{
 					Cores: 1,
 				}
-				Expect(storeHandler.Store(vm)).To(MatchError(conflict.Conflicts{conflict.New("spec", "template", "spec", "domain", "cpu", "cores")}))
+				Expect(storeHandler.Store(vm)).To(MatchError(
+					conflict.Conflicts{conflict.New("spec", "template", "spec", "domain", "cpu", "cores")}.WithSource(conflict.SourceInstancetype)))
 			})
 		})
 	})
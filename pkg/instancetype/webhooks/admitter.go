@@ -28,6 +28,7 @@ func ValidateInstanceTypeSpec(field *k8sfield.Path, spec *instancetypev1beta1.Vi
 
 	causes = append(causes, validateMemoryOvercommitPercentSetting(field, spec)...)
 	causes = append(causes, validateMemoryOvercommitPercentNoHugepages(field, spec)...)
+	causes = append(causes, validateMemoryMaxGuest(field, spec)...)
 	return causes
 }
 
@@ -62,6 +63,22 @@ func validateMemoryOvercommitPercentNoHugepages(
 	return causes
 }
 
+func validateMemoryMaxGuest(
+	field *k8sfield.Path,
+	spec *instancetypev1beta1.VirtualMachineInstancetypeSpec,
+) (causes []metav1.StatusCause) {
+	if spec.Memory.MaxGuest != nil && spec.Memory.MaxGuest.Cmp(spec.Memory.Guest) < 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type: metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s '%s' must be greater than or equal to %s '%s'.",
+				field.Child("memory", "maxGuest").String(), spec.Memory.MaxGuest.String(),
+				field.Child("memory", "guest").String(), spec.Memory.Guest.String()),
+			Field: field.Child("memory", "maxGuest").String(),
+		})
+	}
+	return causes
+}
+
 type ClusterInstancetypeAdmitter struct{}
 
 func (f *ClusterInstancetypeAdmitter) Admit(_ context.Context, ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
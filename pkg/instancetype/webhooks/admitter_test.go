@@ -89,6 +89,28 @@ var _ = Describe("Validating Instancetype Admitter", func() {
 		Expect(response.Result.Code).To(
 			Equal(int32(http.StatusUnprocessableEntity)), "overCommitPercent and hugepages should not be requested together.")
 	})
+
+	It("should reject specs with maxGuest less than guest memory", func() {
+		version := instancetypev1beta1.SchemeGroupVersion.Version
+		maxGuest := resource.MustParse("64M")
+		instancetypeObj.Spec = instancetypev1beta1.VirtualMachineInstancetypeSpec{
+			CPU: instancetypev1beta1.CPUInstancetype{
+				Guest: uint32(1),
+			},
+			Memory: instancetypev1beta1.MemoryInstancetype{
+				Guest:    resource.MustParse("128M"),
+				MaxGuest: &maxGuest,
+			},
+		}
+		ar := createInstancetypeAdmissionReview(instancetypeObj, version)
+		response := admitter.Admit(context.Background(), ar)
+
+		Expect(response.Allowed).To(BeFalse(), "Expected instancetype to not be allowed")
+		Expect(response.Result.Details.Causes).To(HaveLen(1))
+		Expect(response.Result.Details.Causes[0].Field).To(Equal("spec.memory.maxGuest"))
+		Expect(response.Result.Details.Causes[0].Message).To(
+			Equal("spec.memory.maxGuest '64M' must be greater than or equal to spec.memory.guest '128M'."))
+	})
 })
 
 var _ = Describe("Validating ClusterInstancetype Admitter", func() {
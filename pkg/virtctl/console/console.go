@@ -20,16 +20,23 @@
 package console
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
 	"kubevirt.io/client-go/kubecli"
 	kvcorev1 "kubevirt.io/client-go/kubevirt/typed/core/v1"
 
@@ -38,7 +45,26 @@ import (
 )
 
 type consoleCommand struct {
-	timeout int
+	timeout       int
+	attachTimeout time.Duration
+	outputPipe    string
+	attach        attachOptions
+}
+
+// attachOptions control how input and output are copied between the terminal and the console
+type attachOptions struct {
+	inputDelay time.Duration
+	lineMode   bool
+	// outputPipe additionally receives the output of the console when set
+	outputPipe io.Writer
+	localEcho  bool
+	// escapeOutput escapes the control bytes of the output, only requested for output that is not a terminal
+	escapeOutput bool
+	// echo receives the input sent to the console when local echo is enabled outside of line mode
+	echo *localEcho
+	// detachSequence is an additional sequence of keys detaching from the console, matched by detachMatcher
+	detachSequence string
+	detachMatcher  *detachSequenceMatcher
 }
 
 func NewCommand() *cobra.Command {
@@ -46,20 +72,60 @@ func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "console (VMI)",
 		Short:   "Connect to a console of a virtual machine instance.",
+		Long:    long(),
 		Example: usage(),
 		Args:    cobra.ExactArgs(1),
 		RunE:    c.run,
 	}
 	cmd.Flags().IntVar(&c.timeout, "timeout", 5, "The number of minutes to wait for the virtual machine instance to be ready.")
+	cmd.Flags().DurationVar(&c.attachTimeout, "attach-timeout", defaultAttachTimeout,
+		"The time allowed to connect to the console once the virtual machine instance is ready.")
+	cmd.Flags().DurationVar(&c.attach.inputDelay, "input-delay", 0,
+		"The delay between chunks of large inputs such as pastes, allowing slow guests to keep up. Disabled by default.")
+	cmd.Flags().BoolVar(&c.attach.lineMode, "line-mode", false,
+		"Buffer and echo input locally, only sending it to the console on Enter. Useful with chatty guests over laggy links.")
+	cmd.Flags().BoolVar(&c.attach.localEcho, "local-echo", false,
+		"Echo input locally for guests that do not echo it, except after a password prompt. Has no effect with --line-mode.")
+	cmd.Flags().BoolVar(&c.attach.escapeOutput, "escape-output", false,
+		"Escape control bytes of the output as \\xNN when stdout is not a terminal, keeping redirected output readable.")
+	cmd.Flags().StringVar(&c.attach.detachSequence, "detach-sequence", "",
+		"An additional sequence of keys such as ~. to detach from the console with. Keys starting the sequence are only sent once it is broken.")
+	cmd.Flags().StringVar(&c.outputPipe, "output-pipe", "",
+		"The path of an existing named pipe to additionally write the output of the console to, allowing it to be consumed by external tooling.")
 	cmd.SetUsageTemplate(templates.UsageTemplate())
 	return cmd
 }
 
+func long() string {
+	return fmt.Sprintf(`Connect to a console of a virtual machine instance.
+
+The exit code reflects why the console was left:
+  %d  the user detached from the console
+  %d  the virtual machine instance was no longer running once the console was disconnected
+  %d  the virtual machine instance was not running or its console could not be connected to in time
+  %d  any other error`,
+		ExitCodeDetached, ExitCodePowerOff, ExitCodeTimeout, ExitCodeError)
+}
+
 func usage() string {
 	usage := `  # Connect to the console on VirtualMachineInstance 'myvmi':
   {{ProgramName}} console myvmi
   # Configure one minute timeout (default 5 minutes)
-  {{ProgramName}} console --timeout=1 myvmi`
+  {{ProgramName}} console --timeout=1 myvmi
+  # Give up if the console can not be connected to within 10 seconds of the VirtualMachineInstance being ready
+  {{ProgramName}} console --attach-timeout=10s myvmi
+  # Pace large pastes for slow guests by waiting 10 milliseconds between chunks of input
+  {{ProgramName}} console --input-delay=10ms myvmi
+  # Edit input locally and only send it to the console on Enter
+  {{ProgramName}} console --line-mode myvmi
+  # Echo input locally for a guest that does not echo it
+  {{ProgramName}} console --local-echo myvmi
+  # Detach from the console by typing ~ followed by . in addition to Ctrl+] or Ctrl+5
+  {{ProgramName}} console --detach-sequence='~.' myvmi
+  # Keep the output readable when redirecting it to a file
+  {{ProgramName}} console --escape-output myvmi > myvmi-console.log
+  # Additionally write the output of the console to an existing named pipe
+  {{ProgramName}} console --output-pipe=/tmp/myvmi-console myvmi`
 
 	return usage
 }
@@ -72,7 +138,16 @@ func (c *consoleCommand) run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot obtain KubeVirt client: %v", err)
 	}
 
-	return c.handleConsoleConnection(client, namespace, vmi)
+	if c.outputPipe != "" {
+		pipe, err := newOutputPipe(c.outputPipe)
+		if err != nil {
+			return err
+		}
+		defer pipe.Close()
+		c.attach.outputPipe = pipe
+	}
+
+	return exitErrorFor(c.handleConsoleConnection(client, namespace, vmi))
 }
 
 func (c *consoleCommand) handleConsoleConnection(client kubecli.KubevirtClient, namespace, vmi string) error {
@@ -88,7 +163,12 @@ func (c *consoleCommand) handleConsoleConnection(client kubecli.KubevirtClient,
 	signal.Notify(waitInterrupt, os.Interrupt)
 
 	go func() {
-		con, err := client.VirtualMachineInstance(namespace).SerialConsole(vmi, &kvcorev1.SerialConsoleOptions{ConnectionTimeout: time.Duration(c.timeout) * time.Minute})
+		// Wait for the VMI explicitly as connecting to the console of a VMI that is not running yet may block
+		if err := waitForVMIRunning(client, namespace, vmi, readyPollInterval, time.Duration(c.timeout)*time.Minute); err != nil {
+			runningChan <- err
+			return
+		}
+		con, err := connectSerialConsole(client, namespace, vmi, c.attachTimeout)
 		runningChan <- err
 
 		if err != nil {
@@ -104,49 +184,133 @@ func (c *consoleCommand) handleConsoleConnection(client kubecli.KubevirtClient,
 	select {
 	case <-waitInterrupt:
 		// Make a new line in the terminal
-		fmt.Println()
+		printNotice(noticeColorNone, "\n")
 		return nil
 	case err := <-runningChan:
 		if err != nil {
-			return err
+			return permissionErrorFor(err, namespace, vmi)
 		}
 	}
-	err := Attach(stdinReader, stdoutReader, stdinWriter, stdoutWriter,
+	templates.PrintWarningForPausedVMI(client, vmi, namespace)
+
+	err := attach(stdinReader, stdoutReader, stdinWriter, stdoutWriter,
 		fmt.Sprintf("Successfully connected to %s console. Press Ctrl+] or Ctrl+5 to exit console.\n", vmi),
-		resChan)
+		resChan, c.attach)
 
+	if errors.Is(err, ErrUserDetached) {
+		return nil
+	}
 	if err != nil {
-		if e, ok := err.(*websocket.CloseError); ok && e.Code == websocket.CloseAbnormalClosure {
-			fmt.Fprint(os.Stderr, "\n"+
-				"You were disconnected from the console. This could be caused by one of the following:"+
-				"\n - the target VM was powered off"+
-				"\n - another user connected to the console of the target VM"+
-				"\n - network issues\n")
+		if waitForVMIPoweredOff(client, namespace, vmi, poweredOffPollInterval, poweredOffTimeout) {
+			printNotice(noticeColorYellow, disconnectMessage(DisconnectReasonPowerOff))
+			return fmt.Errorf("virtual machine instance %s was %w: %w", vmi, errPoweredOff, err)
+		}
+		var closeErr *websocket.CloseError
+		if reason := DisconnectReasonFor(err); reason != DisconnectReasonUnknown || errors.As(err, &closeErr) {
+			printNotice(noticeColorYellow, disconnectMessage(reason))
 		}
 		return err
 	}
 	return nil
 }
 
+const defaultAttachTimeout = time.Minute
+
+// connectSerialConsole connects to the serial console of a ready VMI, failing once attachTimeout has passed without
+// the connection being established so that a VMI that is running but unreachable does not leave virtctl hanging.
+// The timeout also bounds the websocket handshake and any retries made while connecting. Connecting can not be
+// cancelled, so any connection only established after the timeout is closed as soon as it arrives.
+func connectSerialConsole(
+	client kubecli.KubevirtClient, namespace, name string, attachTimeout time.Duration,
+) (kvcorev1.StreamInterface, error) {
+	type connection struct {
+		con kvcorev1.StreamInterface
+		err error
+	}
+	connected := make(chan connection, 1)
+	go func() {
+		con, err := client.VirtualMachineInstance(namespace).SerialConsole(name, &kvcorev1.SerialConsoleOptions{
+			ConnectionTimeout: attachTimeout,
+			Dialer:            &websocket.Dialer{HandshakeTimeout: attachTimeout},
+		})
+		connected <- connection{con: con, err: err}
+	}()
+
+	select {
+	case c := <-connected:
+		return c.con, c.err
+	case <-time.After(attachTimeout):
+		go func() {
+			if c := <-connected; c.con != nil {
+				_ = c.con.AsConn().Close()
+			}
+		}()
+		return nil, fmt.Errorf("%w after %s connecting to the console of virtual machine instance %s", errTimeout, attachTimeout, name)
+	}
+}
+
+// permissionErrorFor returns a clear error when err was caused by the user not being allowed to open the console of the
+// VMI, telling it apart from the VMI not being found or not running. Any other error is returned unchanged.
+func permissionErrorFor(err error, namespace, name string) error {
+	denied := k8serrors.IsForbidden(err) || k8serrors.IsUnauthorized(err)
+	var asyncErr *kvcorev1.AsyncSubresourceError
+	if errors.As(err, &asyncErr) {
+		denied = asyncErr.GetStatusCode() == http.StatusForbidden || asyncErr.GetStatusCode() == http.StatusUnauthorized
+	}
+	if !denied {
+		return err
+	}
+	return fmt.Errorf("you don't have permission to access the console of virtual machine instance %s/%s: %w", namespace, name, err)
+}
+
+// ErrUserDetached is returned by Attach when the user detached from the console using the escape
+// sequence or an interrupt, allowing a clean exit to be told apart from the connection being dropped
+var ErrUserDetached = errors.New("user detached from the console")
+
 // Attach attaches stdin and stdout to the console
 // in -> stdinWriter | stdinReader -> console
 // out <- stdoutReader | stdoutWriter <- console
-func Attach(stdinReader, stdoutReader *io.PipeReader, stdinWriter, stdoutWriter *io.PipeWriter, message string, resChan <-chan error) (err error) {
+func Attach(stdinReader, stdoutReader *io.PipeReader, stdinWriter, stdoutWriter *io.PipeWriter, message string, resChan <-chan error) error {
+	return attach(stdinReader, stdoutReader, stdinWriter, stdoutWriter, message, resChan, attachOptions{})
+}
+
+func attach(
+	stdinReader, stdoutReader *io.PipeReader,
+	stdinWriter, stdoutWriter *io.PipeWriter,
+	message string,
+	resChan <-chan error,
+	opts attachOptions,
+) error {
 	stopChan := make(chan struct{}, 1)
 	writeStop := make(chan error)
 	readStop := make(chan error)
-	if term.IsTerminal(int(os.Stdin.Fd())) {
+	restoreTerminal := func() {}
+	// In line mode the terminal is left in canonical mode so that input is echoed and can be edited locally
+	if !opts.lineMode && term.IsTerminal(int(os.Stdin.Fd())) {
 		state, err := term.MakeRaw(int(os.Stdin.Fd()))
 		if err != nil {
 			return fmt.Errorf("Make raw terminal failed: %s", err)
 		}
-		defer term.Restore(int(os.Stdin.Fd()), state)
+		// The terminal is restored once, by whichever of returning or a panicking copy goroutine comes first
+		restoreTerminal = sync.OnceFunc(func() {
+			_ = term.Restore(int(os.Stdin.Fd()), state)
+		})
+		defer restoreTerminal()
 	}
-	fmt.Fprint(os.Stderr, message)
+	printNotice(noticeColorNone, message)
 
 	in := os.Stdin
 	out := os.Stdout
 
+	opts.escapeOutput = escapesOutput(opts.escapeOutput, int(out.Fd()))
+	// In line mode input is already echoed by the terminal
+	if opts.localEcho && !opts.lineMode {
+		opts.echo = newLocalEcho(out)
+	}
+	if opts.detachSequence != "" {
+		opts.detachMatcher = newDetachSequenceMatcher(opts.detachSequence)
+	}
+
 	go func() {
 		interrupt := make(chan os.Signal, 1)
 		signal.Notify(interrupt, os.Interrupt)
@@ -155,42 +319,193 @@ func Attach(stdinReader, stdoutReader *io.PipeReader, stdinWriter, stdoutWriter
 	}()
 
 	go func() {
-		_, err := io.Copy(out, stdoutReader)
-		readStop <- err
+		defer restoreOnPanic(restoreTerminal)
+		readStop <- handleOutputCopy(out, stdoutReader, opts)
 	}()
 
 	go func() {
+		defer restoreOnPanic(restoreTerminal)
 		defer close(writeStop)
-		buf := make([]byte, 1024, 1024)
-		for {
-			// reading from stdin
-			n, err := in.Read(buf)
-			if err != nil && err != io.EOF {
-				writeStop <- err
-				return
-			}
-			if n == 0 && err == io.EOF {
-				return
-			}
-
-			// the escape sequence
-			if buf[0] == 29 {
-				return
-			}
-			// Writing out to the console connection
-			_, err = stdinWriter.Write(buf[0:n])
-			if err == io.EOF {
-				return
-			}
+		if err := handleInputCopy(in, stdinWriter, opts); err != nil {
+			writeStop <- err
 		}
 	}()
 
+	return waitForDetach(stopChan, readStop, writeStop, resChan)
+}
+
+// restoreOnPanic is deferred by the copy goroutines of attach. A panic in a goroutine ends the process without
+// running the deferred calls of attach, so the terminal is restored here before re-raising the panic to avoid
+// leaving the shell of the user in raw mode.
+func restoreOnPanic(restoreTerminal func()) {
+	if r := recover(); r != nil {
+		restoreTerminal()
+		panic(r)
+	}
+}
+
+// waitForDetach returns the reason the first of the interrupt, output, input and stream goroutines stopped.
+// An interrupt is reported as ErrUserDetached and the end of input, when writeStop is closed, as nil.
+func waitForDetach(stopChan <-chan struct{}, readStop, writeStop, resChan <-chan error) error {
 	select {
 	case <-stopChan:
-	case err = <-readStop:
-	case err = <-writeStop:
-	case err = <-resChan:
+		return ErrUserDetached
+	case err := <-readStop:
+		return err
+	case err := <-writeStop:
+		return err
+	case err := <-resChan:
+		return err
 	}
+}
 
+// handleOutputCopy copies the output of the console to out, escaping its control bytes when requested, and any output pipe.
+// Any local echo also receives the output to watch for password prompts.
+func handleOutputCopy(out io.Writer, in io.Reader, opts attachOptions) error {
+	if opts.escapeOutput {
+		out = controlEscapingWriter{out: out}
+	}
+	writers := []io.Writer{out}
+	if opts.outputPipe != nil {
+		writers = append(writers, opts.outputPipe)
+	}
+	if opts.echo != nil {
+		writers = append(writers, opts.echo)
+	}
+	_, err := io.Copy(io.MultiWriter(writers...), in)
 	return err
 }
+
+// escapeByte is sent by terminals for both Ctrl+] and Ctrl+5
+const escapeByte = 29
+
+// ctrl5Sequences are sent for Ctrl+5 by terminals reporting modified keys, using the
+// xterm modifyOtherKeys and CSI u encodings respectively
+var ctrl5Sequences = [][]byte{
+	[]byte("\x1b[27;5;53~"),
+	[]byte("\x1b[53;5u"),
+}
+
+// isEscapeSequence reports whether the input read from stdin starts with Ctrl+] or Ctrl+5
+func isEscapeSequence(input []byte) bool {
+	if len(input) > 0 && input[0] == escapeByte {
+		return true
+	}
+	for _, sequence := range ctrl5Sequences {
+		if bytes.HasPrefix(input, sequence) {
+			return true
+		}
+	}
+	return false
+}
+
+// detachInput returns the input to forward to the console and whether it detaches from the console,
+// either with Ctrl+] or Ctrl+5 or by completing any detach sequence
+func detachInput(input []byte, opts attachOptions) ([]byte, bool) {
+	if isEscapeSequence(input) {
+		return nil, true
+	}
+	if opts.detachMatcher == nil {
+		return input, false
+	}
+	return opts.detachMatcher.match(input)
+}
+
+// inputChunkSize is the size of the chunks large inputs are split into when an input delay is requested
+const inputChunkSize = 64
+
+// handleInputCopy copies from in to the console connection until the escape or detach sequence is read, returning ErrUserDetached.
+// When a delay is set, reads larger than inputChunkSize are written in chunks separated by the delay
+// so that slow guests are not overrun, while the single bytes of normal typing are written immediately.
+// In line mode input is accumulated into a line, applying any backspaces, and only written on Enter.
+// Input is echoed as it is read when local echo is enabled.
+func handleInputCopy(in io.Reader, out io.Writer, opts attachOptions) error {
+	buf := make([]byte, 1024)
+	var line lineBuffer
+	for {
+		// reading from stdin
+		n, err := in.Read(buf)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if n == 0 && err == io.EOF {
+			flushInput(out, line, opts)
+			return nil
+		}
+
+		input, detached := detachInput(buf[0:n], opts)
+		if detached {
+			return ErrUserDetached
+		}
+		if len(input) == 0 {
+			continue
+		}
+		if opts.echo != nil {
+			opts.echo.echo(input)
+		}
+		if opts.lineMode {
+			input = line.add(input)
+			if len(input) == 0 {
+				continue
+			}
+		}
+		// Writing out to the console connection
+		if err := writeInput(out, input, opts.inputDelay); err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// flushInput writes any input still held back at the end of input, such as a final line of piped input without a newline
+// or keys that started but never completed the detach sequence
+func flushInput(out io.Writer, line lineBuffer, opts attachOptions) {
+	remaining := opts.detachMatcher.flush()
+	if opts.lineMode {
+		remaining = append(slices.Clone(line), remaining...)
+	}
+	if len(remaining) > 0 {
+		_ = writeInput(out, remaining, opts.inputDelay)
+	}
+}
+
+const (
+	backspace = '\b'
+	del       = 0x7f
+)
+
+// lineBuffer accumulates input until a complete line has been entered
+type lineBuffer []byte
+
+// add appends input to the line, applying backspaces, and returns any completed lines which are removed from the buffer
+func (l *lineBuffer) add(input []byte) []byte {
+	var completed []byte
+	for _, b := range input {
+		switch b {
+		case backspace, del:
+			if len(*l) > 0 {
+				*l = (*l)[:len(*l)-1]
+			}
+		case '\n', '\r':
+			completed = append(completed, *l...)
+			completed = append(completed, b)
+			*l = (*l)[:0]
+		default:
+			*l = append(*l, b)
+		}
+	}
+	return completed
+}
+
+func writeInput(out io.Writer, input []byte, inputDelay time.Duration) error {
+	if inputDelay == 0 || len(input) <= inputChunkSize {
+		_, err := out.Write(input)
+		return err
+	}
+	for chunk := range slices.Chunk(input, inputChunkSize) {
+		if _, err := out.Write(chunk); err != nil {
+			return err
+		}
+		time.Sleep(inputDelay)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package console
+
+import (
+	"testing"
+
+	"kubevirt.io/client-go/testutils"
+)
+
+func TestConsole(t *testing.T) {
+	testutils.KubeVirtTestSuiteSetup(t)
+}
@@ -0,0 +1,342 @@
+package console
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubevirt.io/client-go/kubecli"
+	kvcorev1 "kubevirt.io/client-go/kubevirt/typed/core/v1"
+)
+
+// recordingWriter records each write made to the console connection
+type recordingWriter struct {
+	bytes.Buffer
+	writes []int
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, len(p))
+	return w.Buffer.Write(p)
+}
+
+// fakeStream is a console stream backed by con
+type fakeStream struct {
+	con net.Conn
+}
+
+func (s *fakeStream) Stream(kvcorev1.StreamOptions) error {
+	return nil
+}
+
+func (s *fakeStream) AsConn() net.Conn {
+	return s.con
+}
+
+// closeRecordingConn records whether the console connection was closed
+type closeRecordingConn struct {
+	net.Conn
+	closed atomic.Bool
+}
+
+func (c *closeRecordingConn) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+// keystrokeReader returns each of its keystrokes from a separate read as typing into a terminal would
+type keystrokeReader struct {
+	keystrokes []string
+}
+
+func (r *keystrokeReader) Read(p []byte) (int, error) {
+	if len(r.keystrokes) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.keystrokes[0])
+	r.keystrokes = r.keystrokes[1:]
+	return n, nil
+}
+
+var _ = Describe("handleInputCopy", func() {
+	var out *recordingWriter
+
+	BeforeEach(func() {
+		out = &recordingWriter{}
+	})
+
+	DescribeTable("should copy a bulk write in full", func(opts attachOptions, maxWriteSize int) {
+		paste := bytes.Repeat([]byte("pasted input\n"), 1000)
+
+		Expect(handleInputCopy(bytes.NewReader(paste), out, opts)).To(Succeed())
+		Expect(out.Bytes()).To(Equal(paste))
+		for _, size := range out.writes {
+			Expect(size).To(BeNumerically("<=", maxWriteSize))
+		}
+	},
+		Entry("without an input delay", attachOptions{}, 1024),
+		Entry("with an input delay in chunks", attachOptions{inputDelay: time.Microsecond}, inputChunkSize),
+	)
+
+	It("should write typed input immediately with an input delay", func() {
+		typed := bytes.Repeat([]byte("a"), inputChunkSize)
+
+		Expect(handleInputCopy(bytes.NewReader(typed), out, attachOptions{inputDelay: time.Hour})).To(Succeed())
+		Expect(out.Bytes()).To(Equal(typed))
+		Expect(out.writes).To(HaveLen(1))
+	})
+
+	DescribeTable("should stop copying at the escape sequence", func(escape []byte) {
+		in := bytes.NewReader(append(escape, []byte("after")...))
+
+		Expect(handleInputCopy(in, out, attachOptions{})).To(MatchError(ErrUserDetached))
+		Expect(out.writes).To(BeEmpty())
+	},
+		Entry("of Ctrl+] and Ctrl+5", []byte{29}),
+		Entry("of Ctrl+5 with xterm modifyOtherKeys", []byte("\x1b[27;5;53~")),
+		Entry("of Ctrl+5 with CSI u", []byte("\x1b[53;5u")),
+	)
+
+	DescribeTable("should copy sequences that are not the escape sequence", func(input []byte) {
+		Expect(handleInputCopy(bytes.NewReader(input), out, attachOptions{})).To(Succeed())
+		Expect(out.Bytes()).To(Equal(input))
+	},
+		Entry("of Ctrl+4 with xterm modifyOtherKeys", []byte("\x1b[27;5;52~")),
+		Entry("of the digit 5", []byte("5")),
+		Entry("of an incomplete CSI u sequence", []byte("\x1b[53")),
+	)
+
+	Context("in line mode", func() {
+		lineMode := attachOptions{lineMode: true}
+
+		DescribeTable("should only write completed lines", func(keystrokes []string, expectedWrites []string) {
+			Expect(handleInputCopy(&keystrokeReader{keystrokes: keystrokes}, out, lineMode)).To(Succeed())
+
+			var writes []string
+			written := out.String()
+			for _, size := range out.writes {
+				writes = append(writes, written[:size])
+				written = written[size:]
+			}
+			Expect(writes).To(Equal(expectedWrites))
+		},
+			Entry("when Enter is pressed",
+				[]string{"l", "s", "\r", "p", "w", "d", "\r"},
+				[]string{"ls\r", "pwd\r"},
+			),
+			Entry("after applying backspaces",
+				[]string{"l", "x", "\x7f", "s", "a", "\b", "\n"},
+				[]string{"ls\n"},
+			),
+			Entry("ignoring backspaces on an empty line",
+				[]string{"\x7f", "\x7f", "l", "s", "\n"},
+				[]string{"ls\n"},
+			),
+			Entry("when several lines are read at once",
+				[]string{"ls\npwd\nwho"},
+				[]string{"ls\npwd\n", "who"},
+			),
+			Entry("flushing the remaining line at the end of input",
+				[]string{"e", "x", "i", "t"},
+				[]string{"exit"},
+			),
+		)
+
+		It("should stop copying at the escape sequence without writing the buffered line", func() {
+			Expect(handleInputCopy(&keystrokeReader{keystrokes: []string{"l", "s", "\x1d"}}, out, lineMode)).To(MatchError(ErrUserDetached))
+			Expect(out.writes).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("restoreOnPanic", func() {
+	It("should restore the terminal and re-raise a panic in a copy goroutine", func() {
+		restored := make(chan struct{})
+		repanicked := make(chan any, 1)
+
+		go func() {
+			defer func() {
+				repanicked <- recover()
+			}()
+			defer restoreOnPanic(func() { close(restored) })
+			panic("copy failed")
+		}()
+
+		Eventually(restored).Should(BeClosed())
+		Eventually(repanicked).Should(Receive(Equal("copy failed")))
+	})
+
+	It("should not restore the terminal when a copy goroutine returns", func() {
+		restored := false
+		func() {
+			defer restoreOnPanic(func() { restored = true })
+		}()
+		Expect(restored).To(BeFalse())
+	})
+})
+
+var _ = Describe("connectSerialConsole", func() {
+	const (
+		vmiName       = "testvmi"
+		attachTimeout = 50 * time.Millisecond
+	)
+
+	var (
+		client    *kubecli.MockKubevirtClient
+		vmiClient *kubecli.MockVirtualMachineInstanceInterface
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		client = kubecli.NewMockKubevirtClient(ctrl)
+		vmiClient = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		client.EXPECT().VirtualMachineInstance(metav1.NamespaceDefault).Return(vmiClient).AnyTimes()
+	})
+
+	It("should fail once the attach timeout passes without completing the handshake", func() {
+		handshake := make(chan struct{})
+		DeferCleanup(func() { close(handshake) })
+		vmiClient.EXPECT().SerialConsole(vmiName, gomock.Any()).DoAndReturn(
+			func(string, *kvcorev1.SerialConsoleOptions) (kvcorev1.StreamInterface, error) {
+				<-handshake
+				return nil, errors.New("handshake never completed")
+			})
+
+		_, err := connectSerialConsole(client, metav1.NamespaceDefault, vmiName, attachTimeout)
+		Expect(err).To(MatchError("timeout after 50ms connecting to the console of virtual machine instance testvmi"))
+		Expect(err).To(MatchError(errTimeout))
+	})
+
+	It("should close a connection established after the attach timeout", func() {
+		handshake := make(chan struct{})
+		con := &closeRecordingConn{}
+		vmiClient.EXPECT().SerialConsole(vmiName, gomock.Any()).DoAndReturn(
+			func(string, *kvcorev1.SerialConsoleOptions) (kvcorev1.StreamInterface, error) {
+				<-handshake
+				return &fakeStream{con: con}, nil
+			})
+
+		_, err := connectSerialConsole(client, metav1.NamespaceDefault, vmiName, attachTimeout)
+		Expect(err).To(MatchError(errTimeout))
+		Expect(con.closed.Load()).To(BeFalse())
+
+		close(handshake)
+		Eventually(con.closed.Load).Should(BeTrue())
+	})
+
+	It("should bound connecting and the websocket handshake by the attach timeout", func() {
+		vmiClient.EXPECT().SerialConsole(vmiName, gomock.Any()).DoAndReturn(
+			func(_ string, options *kvcorev1.SerialConsoleOptions) (kvcorev1.StreamInterface, error) {
+				Expect(options.ConnectionTimeout).To(Equal(attachTimeout))
+				Expect(options.Dialer.HandshakeTimeout).To(Equal(attachTimeout))
+				return nil, nil
+			})
+
+		Expect(connectSerialConsole(client, metav1.NamespaceDefault, vmiName, attachTimeout)).Error().ToNot(HaveOccurred())
+	})
+
+	It("should return errors connecting", func() {
+		vmiClient.EXPECT().SerialConsole(vmiName, gomock.Any()).Return(nil, errors.New("failure"))
+
+		_, err := connectSerialConsole(client, metav1.NamespaceDefault, vmiName, attachTimeout)
+		Expect(err).To(MatchError("failure"))
+	})
+})
+
+var _ = Describe("permissionErrorFor", func() {
+	const (
+		vmiName       = "testvmi"
+		deniedMessage = "you don't have permission to access the console of virtual machine instance default/testvmi"
+	)
+
+	var (
+		client    *kubecli.MockKubevirtClient
+		vmiClient *kubecli.MockVirtualMachineInstanceInterface
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		client = kubecli.NewMockKubevirtClient(ctrl)
+		vmiClient = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		client.EXPECT().VirtualMachineInstance(metav1.NamespaceDefault).Return(vmiClient).AnyTimes()
+	})
+
+	DescribeTable("should report a denied console connection", func(statusCode int) {
+		vmiClient.EXPECT().SerialConsole(vmiName, gomock.Any()).Return(nil, &kvcorev1.AsyncSubresourceError{StatusCode: statusCode})
+
+		_, err := connectSerialConsole(client, metav1.NamespaceDefault, vmiName, time.Second)
+		err = permissionErrorFor(err, metav1.NamespaceDefault, vmiName)
+		Expect(err).To(MatchError(HavePrefix(deniedMessage)))
+		Expect(errors.As(err, new(*kvcorev1.AsyncSubresourceError))).To(BeTrue())
+	},
+		Entry("when forbidden", http.StatusForbidden),
+		Entry("when unauthorized", http.StatusUnauthorized),
+	)
+
+	DescribeTable("should return other errors unchanged", func(err error) {
+		Expect(permissionErrorFor(err, metav1.NamespaceDefault, vmiName)).To(BeIdenticalTo(err))
+	},
+		Entry("when the VMI is not found", &kvcorev1.AsyncSubresourceError{StatusCode: http.StatusNotFound}),
+		Entry("when the VMI is not running", errors.New("virtual machine instance testvmi has already finished in phase Succeeded")),
+		Entry("when connecting times out", fmt.Errorf("%w after 1s connecting to the console", errTimeout)),
+	)
+})
+
+var _ = Describe("waitForDetach", func() {
+	var (
+		stopChan                     chan struct{}
+		readStop, writeStop, resChan chan error
+	)
+
+	BeforeEach(func() {
+		stopChan = make(chan struct{}, 1)
+		readStop = make(chan error, 1)
+		writeStop = make(chan error, 1)
+		resChan = make(chan error, 1)
+	})
+
+	It("should report an interrupt as a user detach", func() {
+		close(stopChan)
+		Expect(waitForDetach(stopChan, readStop, writeStop, resChan)).To(MatchError(ErrUserDetached))
+	})
+
+	It("should report the escape sequence as a user detach", func() {
+		writeStop <- ErrUserDetached
+		Expect(waitForDetach(stopChan, readStop, writeStop, resChan)).To(MatchError(ErrUserDetached))
+	})
+
+	It("should report the end of input as a clean exit", func() {
+		close(writeStop)
+		Expect(waitForDetach(stopChan, readStop, writeStop, resChan)).To(Succeed())
+	})
+
+	DescribeTable("should report the error of", func(stopped func() chan error) {
+		err := errors.New("stopped")
+		stopped() <- err
+		Expect(waitForDetach(stopChan, readStop, writeStop, resChan)).To(MatchError(err))
+	},
+		Entry("copying input", func() chan error { return writeStop }),
+		Entry("copying output", func() chan error { return readStop }),
+		Entry("the console stream", func() chan error { return resChan }),
+	)
+
+	It("should report a closed console connection", func() {
+		closeErr := &websocket.CloseError{Code: websocket.CloseAbnormalClosure}
+		resChan <- closeErr
+		err := waitForDetach(stopChan, readStop, writeStop, resChan)
+		Expect(err).To(MatchError(closeErr))
+		Expect(err).ToNot(MatchError(ErrUserDetached))
+	})
+})
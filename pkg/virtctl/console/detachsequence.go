@@ -0,0 +1,70 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+
+package console
+
+import (
+	"bytes"
+)
+
+// detachSequenceMatcher detects a custom multi-byte detach sequence such as ~. within the input, which may be split
+// across reads. Bytes that could be the start of the sequence are held back until the sequence is either completed
+// or broken by another byte, in which case they are forwarded along with the rest of the input.
+type detachSequenceMatcher struct {
+	sequence []byte
+	// held is the start of the sequence read so far
+	held []byte
+}
+
+func newDetachSequenceMatcher(sequence string) *detachSequenceMatcher {
+	return &detachSequenceMatcher{sequence: []byte(sequence)}
+}
+
+// match returns the input to forward to the console and whether the detach sequence has been completed
+func (m *detachSequenceMatcher) match(input []byte) ([]byte, bool) {
+	if len(m.held) == 0 && !bytes.Contains(input, m.sequence[:1]) {
+		return input, false
+	}
+	var forward []byte
+	for _, b := range input {
+		m.held = append(m.held, b)
+		if bytes.Equal(m.held, m.sequence) {
+			m.held = nil
+			return forward, true
+		}
+		// Forward held bytes until the remainder could still be the start of the sequence
+		i := 0
+		for !bytes.HasPrefix(m.sequence, m.held[i:]) {
+			i++
+		}
+		forward = append(forward, m.held[:i]...)
+		m.held = m.held[i:]
+	}
+	return forward, false
+}
+
+// flush returns and forgets any bytes held back, such as at the end of input
+func (m *detachSequenceMatcher) flush() []byte {
+	if m == nil {
+		return nil
+	}
+	held := m.held
+	m.held = nil
+	return held
+}
@@ -0,0 +1,62 @@
+package console
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Detach sequence", func() {
+	var out *recordingWriter
+
+	BeforeEach(func() {
+		out = &recordingWriter{}
+	})
+
+	withDetachSequence := func(sequence string) attachOptions {
+		return attachOptions{detachMatcher: newDetachSequenceMatcher(sequence)}
+	}
+
+	DescribeTable("should detach once the sequence is completed", func(sequence string, keystrokes []string, expected string) {
+		Expect(handleInputCopy(&keystrokeReader{keystrokes: keystrokes}, out, withDetachSequence(sequence))).To(MatchError(ErrUserDetached))
+		Expect(out.String()).To(Equal(expected))
+	},
+		Entry("when read at once", "~.", []string{"~."}, ""),
+		Entry("when split across reads", "~.", []string{"~", "."}, ""),
+		Entry("when interleaved with other input", "~.", []string{"l", "s", "\r", "~", "."}, "ls\r"),
+		Entry("when completed within a read of other input", "~.", []string{"ls\r~", ".whoami"}, "ls\r"),
+		Entry("after a broken start of the sequence", "~.", []string{"~", "x", "~", "."}, "~x"),
+		Entry("after a repeated first byte", "~.", []string{"~", "~", "."}, "~"),
+		Entry("when overlapping a broken longer sequence", "~~.", []string{"~", "~", "~", "."}, "~"),
+	)
+
+	DescribeTable("should forward input that does not complete the sequence", func(keystrokes []string, expectedWrites []string) {
+		Expect(handleInputCopy(&keystrokeReader{keystrokes: keystrokes}, out, withDetachSequence("~."))).To(Succeed())
+
+		var writes []string
+		written := out.String()
+		for _, size := range out.writes {
+			writes = append(writes, written[:size])
+			written = written[size:]
+		}
+		Expect(writes).To(Equal(expectedWrites))
+	},
+		Entry("without the first byte", []string{"l", "s"}, []string{"l", "s"}),
+		Entry("holding back the first byte until it is broken", []string{"~", "/", "x"}, []string{"~/", "x"}),
+		Entry("within a single read", []string{"cd ~/src"}, []string{"cd ~/src"}),
+		Entry("flushing a held back first byte at the end of input", []string{"l", "~"}, []string{"l", "~"}),
+	)
+
+	It("should still detach with Ctrl+]", func() {
+		keystrokes := &keystrokeReader{keystrokes: []string{"l", "\x1d"}}
+		Expect(handleInputCopy(keystrokes, out, withDetachSequence("~."))).To(MatchError(ErrUserDetached))
+		Expect(out.String()).To(Equal("l"))
+	})
+
+	It("should flush a held back first byte with the line in line mode", func() {
+		opts := withDetachSequence("~.")
+		opts.lineMode = true
+		Expect(handleInputCopy(&keystrokeReader{keystrokes: []string{"l", "s", "~"}}, out, opts)).To(Succeed())
+		Expect(out.String()).To(Equal("ls~"))
+		Expect(out.writes).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,66 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+
+package console
+
+import (
+	"errors"
+	"net"
+)
+
+// DisconnectReason is a machine-readable cause of a console disconnection
+type DisconnectReason string
+
+const (
+	// DisconnectReasonUnknown is used when the cause of the disconnection cannot be determined
+	DisconnectReasonUnknown DisconnectReason = "Unknown"
+	// DisconnectReasonPowerOff is used when the VMI was no longer running once the console was disconnected
+	DisconnectReasonPowerOff DisconnectReason = "PowerOff"
+	// DisconnectReasonNetwork is used when the connection to the server failed
+	DisconnectReasonNetwork DisconnectReason = "Network"
+)
+
+var disconnectGuidance = map[DisconnectReason]string{
+	DisconnectReasonPowerOff: "the target VM was powered off",
+	DisconnectReasonNetwork:  "of network issues",
+}
+
+// DisconnectReasonFor returns the reason of a console disconnection caused by err.
+// The console servers never send a close frame, so a websocket.CloseError only tells that the connection was closed
+// and not why. Only failures of the network connection itself are mapped to a reason, a power-off can only be told
+// apart by looking at the VMI.
+func DisconnectReasonFor(err error) DisconnectReason {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return DisconnectReasonNetwork
+	}
+	return DisconnectReasonUnknown
+}
+
+// disconnectMessage returns the guidance printed to the user for a disconnection with the given reason
+func disconnectMessage(reason DisconnectReason) string {
+	if guidance, exists := disconnectGuidance[reason]; exists {
+		return "\nYou were disconnected from the console because " + guidance + ".\n"
+	}
+	return "\n" +
+		"You were disconnected from the console. This could be caused by one of the following:" +
+		"\n - the target VM was powered off" +
+		"\n - another user connected to the console of the target VM" +
+		"\n - network issues\n"
+}
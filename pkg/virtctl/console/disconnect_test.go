@@ -0,0 +1,42 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Disconnect reasons", func() {
+	DescribeTable("should map", func(err error, expectedReason DisconnectReason) {
+		Expect(DisconnectReasonFor(err)).To(Equal(expectedReason))
+	},
+		Entry("an abnormal closure to unknown",
+			&websocket.CloseError{Code: websocket.CloseAbnormalClosure}, DisconnectReasonUnknown),
+		Entry("any other close code to unknown",
+			&websocket.CloseError{Code: websocket.CloseGoingAway}, DisconnectReasonUnknown),
+		Entry("a reset connection to network",
+			&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, DisconnectReasonNetwork),
+		Entry("a wrapped timeout to network",
+			fmt.Errorf("stream failed: %w", os.ErrDeadlineExceeded), DisconnectReasonNetwork),
+		Entry("any other error to unknown",
+			errors.New("failure"), DisconnectReasonUnknown),
+	)
+
+	It("should print specific guidance for a known reason", func() {
+		Expect(disconnectMessage(DisconnectReasonPowerOff)).To(Equal(
+			"\nYou were disconnected from the console because the target VM was powered off.\n"))
+	})
+
+	It("should print all possible causes for an unknown reason", func() {
+		message := disconnectMessage(DisconnectReasonUnknown)
+		Expect(message).To(ContainSubstring("the target VM was powered off"))
+		Expect(message).To(ContainSubstring("another user connected to the console of the target VM"))
+		Expect(message).To(ContainSubstring("network issues"))
+	})
+})
@@ -0,0 +1,67 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+
+package console
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+)
+
+// localEcho echoes the input sent to the console for guests that do not echo it themselves.
+// Echoing is suppressed once the console outputs a password prompt until the line answering it has been entered.
+type localEcho struct {
+	out        io.Writer
+	suppressed atomic.Bool
+}
+
+func newLocalEcho(out io.Writer) *localEcho {
+	return &localEcho{out: out}
+}
+
+// Write inspects the output of the console for a password prompt, allowing the echo to be used as an output writer
+func (e *localEcho) Write(output []byte) (int, error) {
+	if isPasswordPrompt(output) {
+		e.suppressed.Store(true)
+	}
+	return len(output), nil
+}
+
+// isPasswordPrompt returns true when the last line of the output mentions a password and ends with a colon
+// such as Password: or [sudo] password for user:
+func isPasswordPrompt(output []byte) bool {
+	line := bytes.TrimRight(output, " \t")
+	if i := bytes.LastIndexAny(line, "\r\n"); i >= 0 {
+		line = line[i+1:]
+	}
+	return bytes.HasSuffix(line, []byte(":")) && bytes.Contains(bytes.ToLower(line), []byte("password"))
+}
+
+// echo writes input to the terminal unless a password is being entered. As the terminal is in raw mode
+// a carriage return is echoed along with a line feed to move to the start of the next line.
+func (e *localEcho) echo(input []byte) {
+	if e.suppressed.Load() {
+		if bytes.ContainsAny(input, "\r\n") {
+			e.suppressed.Store(false)
+		}
+		return
+	}
+	_, _ = e.out.Write(bytes.ReplaceAll(input, []byte("\r"), []byte("\r\n")))
+}
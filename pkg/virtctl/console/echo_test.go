@@ -0,0 +1,59 @@
+package console
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Local echo", func() {
+	var (
+		terminal *bytes.Buffer
+		console  *bytes.Buffer
+		opts     attachOptions
+	)
+
+	BeforeEach(func() {
+		terminal = &bytes.Buffer{}
+		console = &bytes.Buffer{}
+		opts = attachOptions{echo: newLocalEcho(terminal)}
+	})
+
+	It("should echo input as it is sent to the console", func() {
+		Expect(handleInputCopy(&keystrokeReader{keystrokes: []string{"l", "s", "\r"}}, console, opts)).To(Succeed())
+		Expect(console.String()).To(Equal("ls\r"))
+		Expect(terminal.String()).To(Equal("ls\r\n"))
+	})
+
+	DescribeTable("should suppress the echo of the line answering a password prompt", func(prompt string) {
+		Expect(handleOutputCopy(&bytes.Buffer{}, strings.NewReader(prompt), opts)).To(Succeed())
+		keystrokes := []string{"s", "e", "c", "r", "e", "t", "\r", "l", "s", "\r"}
+		Expect(handleInputCopy(&keystrokeReader{keystrokes: keystrokes}, console, opts)).To(Succeed())
+
+		Expect(console.String()).To(Equal("secret\rls\r"))
+		Expect(terminal.String()).To(Equal("ls\r\n"))
+	},
+		Entry("with a capitalized prompt", "Password: "),
+		Entry("with a lowercase prompt", "[sudo] password for user:"),
+	)
+
+	It("should keep echoing when the output only mentions a password", func() {
+		Expect(handleOutputCopy(&bytes.Buffer{}, strings.NewReader("Password: changed\r\n"), opts)).To(Succeed())
+		Expect(handleInputCopy(&keystrokeReader{keystrokes: []string{"l", "s"}}, console, opts)).To(Succeed())
+		Expect(terminal.String()).To(Equal("ls"))
+	})
+
+	It("should still copy the output of the console", func() {
+		out := &bytes.Buffer{}
+		Expect(handleOutputCopy(out, strings.NewReader("login: "), opts)).To(Succeed())
+		Expect(out.String()).To(Equal("login: "))
+	})
+
+	It("should not echo input without local echo", func() {
+		Expect(handleInputCopy(&keystrokeReader{keystrokes: []string{"l", "s"}}, console, attachOptions{})).To(Succeed())
+		Expect(console.String()).To(Equal("ls"))
+		Expect(terminal.String()).To(BeEmpty())
+	})
+})
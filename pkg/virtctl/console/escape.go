@@ -0,0 +1,62 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+
+package console
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/term"
+)
+
+// escapesOutput returns true when escaping of the output was requested and the output is not a terminal,
+// leaving the output of interactive sessions untouched
+func escapesOutput(requested bool, fd int) bool {
+	return requested && !term.IsTerminal(fd)
+}
+
+// controlEscapingWriter escapes control bytes meant for a terminal, such as those of ANSI escape sequences, as \xNN
+// so that output redirected to a file or pipe stays readable. Line feeds, carriage returns and tabs are kept.
+type controlEscapingWriter struct {
+	out io.Writer
+}
+
+func (w controlEscapingWriter) Write(output []byte) (int, error) {
+	escaped := make([]byte, 0, len(output))
+	for _, b := range output {
+		if isEscapedControlByte(b) {
+			escaped = fmt.Appendf(escaped, `\x%02x`, b)
+			continue
+		}
+		escaped = append(escaped, b)
+	}
+	if _, err := w.out.Write(escaped); err != nil {
+		return 0, err
+	}
+	return len(output), nil
+}
+
+func isEscapedControlByte(b byte) bool {
+	switch b {
+	case '\n', '\r', '\t':
+		return false
+	}
+	return b < 0x20 || b == del
+}
@@ -0,0 +1,45 @@
+package console
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Output escaping", func() {
+	const output = "\x1b[1mlogin:\x1b[0m \x07root\r\n\tdone\x7f"
+
+	It("should escape control bytes of output that is not a terminal", func() {
+		out := &bytes.Buffer{}
+		Expect(handleOutputCopy(out, strings.NewReader(output), attachOptions{escapeOutput: true})).To(Succeed())
+		Expect(out.String()).To(Equal(`\x1b[1mlogin:\x1b[0m \x07root` + "\r\n\tdone" + `\x7f`))
+	})
+
+	It("should leave output untouched without escaping", func() {
+		out := &bytes.Buffer{}
+		Expect(handleOutputCopy(out, strings.NewReader(output), attachOptions{})).To(Succeed())
+		Expect(out.String()).To(Equal(output))
+	})
+
+	It("should not escape the output written to an output pipe", func() {
+		out, pipe := &bytes.Buffer{}, &bytes.Buffer{}
+		Expect(handleOutputCopy(out, strings.NewReader(output), attachOptions{escapeOutput: true, outputPipe: pipe})).To(Succeed())
+		Expect(out.String()).ToNot(Equal(output))
+		Expect(pipe.String()).To(Equal(output))
+	})
+
+	DescribeTable("should only escape output that is not a terminal when requested", func(requested, expected bool) {
+		reader, writer, err := os.Pipe()
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(reader.Close)
+		DeferCleanup(writer.Close)
+
+		Expect(escapesOutput(requested, int(writer.Fd()))).To(Equal(expected))
+	},
+		Entry("when requested for a pipe", true, true),
+		Entry("when not requested for a pipe", false, false),
+	)
+})
@@ -0,0 +1,72 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+
+package console
+
+import (
+	"errors"
+)
+
+// Exit codes of the console command, allowing scripts to tell the cause of a disconnection apart.
+// Any other error exits with ExitCodeError.
+const (
+	// ExitCodeDetached is used when the user detached from the console
+	ExitCodeDetached = 0
+	// ExitCodeError is used for errors without a dedicated exit code
+	ExitCodeError = 1
+	// ExitCodePowerOff is used when the VMI was no longer running after the console was disconnected
+	ExitCodePowerOff = 2
+	// ExitCodeTimeout is used when the VMI was not running or the console could not be connected to in time
+	ExitCodeTimeout = 3
+)
+
+var (
+	// errTimeout is wrapped by the errors returned when waiting for the VMI or connecting to its console times out
+	errTimeout = errors.New("timeout")
+	// errPoweredOff is wrapped by the error returned when the console was disconnected as the VMI was powered off
+	errPoweredOff = errors.New("powered off")
+)
+
+// ExitError is returned by the console command for outcomes with a dedicated exit code
+type ExitError struct {
+	Err  error
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// exitErrorFor wraps err in an ExitError when its cause has a dedicated exit code, returning any other error unchanged
+func exitErrorFor(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(err, errTimeout):
+		return &ExitError{Err: err, Code: ExitCodeTimeout}
+	case errors.Is(err, errPoweredOff):
+		return &ExitError{Err: err, Code: ExitCodePowerOff}
+	}
+	return err
+}
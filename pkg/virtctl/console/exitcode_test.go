@@ -0,0 +1,41 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Exit codes", func() {
+	DescribeTable("should exit with", func(err error, expectedCode int) {
+		exitErr := exitErrorFor(err)
+		var consoleExitErr *ExitError
+		Expect(errors.As(exitErr, &consoleExitErr)).To(BeTrue())
+		Expect(consoleExitErr.Code).To(Equal(expectedCode))
+		Expect(consoleExitErr.Error()).To(Equal(err.Error()))
+		Expect(errors.Is(exitErr, err)).To(BeTrue())
+	},
+		Entry("power-off when the VMI was powered off",
+			fmt.Errorf("virtual machine instance testvmi was %w: %w", errPoweredOff, &websocket.CloseError{Code: websocket.CloseAbnormalClosure}),
+			ExitCodePowerOff),
+		Entry("timeout when the VMI was not running in time",
+			fmt.Errorf("%w waiting for virtual machine instance testvmi to be running", errTimeout), ExitCodeTimeout),
+		Entry("timeout when the console could not be connected to in time",
+			fmt.Errorf("%w after 1s connecting to the console of virtual machine instance testvmi", errTimeout), ExitCodeTimeout),
+	)
+
+	It("should exit cleanly when the user detached", func() {
+		Expect(exitErrorFor(nil)).ToNot(HaveOccurred())
+	})
+
+	DescribeTable("should return errors without a dedicated exit code unchanged", func(err error) {
+		Expect(exitErrorFor(err)).To(BeIdenticalTo(err))
+	},
+		Entry("a disconnection while the VMI is still running", &websocket.CloseError{Code: websocket.CloseAbnormalClosure}),
+		Entry("a network disconnection", &websocket.CloseError{Code: websocket.CloseServiceRestart}),
+		Entry("any other error", errors.New("failure")),
+	)
+})
@@ -0,0 +1,56 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+
+package console
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// noticeColor is the ANSI SGR code used to color a notice
+type noticeColor string
+
+const (
+	noticeColorNone   noticeColor = ""
+	noticeColorYellow noticeColor = "33"
+)
+
+// printNotice writes a notice to stderr, keeping it out of any captured stdout of the console.
+// The notice is only colored when stderr is a terminal and NO_COLOR is not set.
+func printNotice(color noticeColor, message string) {
+	fmt.Fprint(os.Stderr, formatNotice(color, message, colorEnabled(os.Stderr)))
+}
+
+func formatNotice(color noticeColor, message string, enabled bool) string {
+	if color == noticeColorNone || !enabled {
+		return message
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m", color, message)
+}
+
+// colorEnabled follows https://no-color.org by disabling color whenever NO_COLOR is set to a non-empty value
+func colorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,40 @@
+package console
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Notices", func() {
+	DescribeTable("should format", func(color noticeColor, enabled bool, expected string) {
+		Expect(formatNotice(color, "notice\n", enabled)).To(Equal(expected))
+	},
+		Entry("a colored notice when color is enabled", noticeColorYellow, true, "\x1b[33mnotice\n\x1b[0m"),
+		Entry("a colored notice without color when color is disabled", noticeColorYellow, false, "notice\n"),
+		Entry("an uncolored notice when color is enabled", noticeColorNone, true, "notice\n"),
+	)
+
+	Context("colorEnabled", func() {
+		var reader, writer *os.File
+
+		BeforeEach(func() {
+			var err error
+			reader, writer, err = os.Pipe()
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(reader.Close)
+			DeferCleanup(writer.Close)
+		})
+
+		It("should be disabled when NO_COLOR is set", func() {
+			GinkgoT().Setenv("NO_COLOR", "1")
+			Expect(colorEnabled(writer)).To(BeFalse())
+		})
+
+		It("should be disabled when not writing to a terminal", func() {
+			GinkgoT().Setenv("NO_COLOR", "")
+			Expect(colorEnabled(writer)).To(BeFalse())
+		})
+	})
+})
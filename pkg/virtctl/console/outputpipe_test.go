@@ -0,0 +1,85 @@
+//go:build !windows
+
+package console
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("outputPipe", func() {
+	var (
+		path string
+		pipe io.WriteCloser
+	)
+
+	openReader := func() *os.File {
+		reader, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+		Expect(err).ToNot(HaveOccurred())
+		return reader
+	}
+
+	expectRead := func(reader *os.File, expected string) {
+		buf := make([]byte, len(expected))
+		_, err := io.ReadFull(reader, buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(buf)).To(Equal(expected))
+	}
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "console")
+		Expect(syscall.Mkfifo(path, 0o600)).To(Succeed())
+
+		var err error
+		pipe, err = newOutputPipe(path)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(pipe.Close)
+	})
+
+	It("should drop output written before a reader connects", func() {
+		Expect(pipe.Write([]byte("dropped"))).To(Equal(len("dropped")))
+
+		reader := openReader()
+		defer reader.Close()
+		Expect(pipe.Write([]byte("output"))).To(Equal(len("output")))
+		expectRead(reader, "output")
+	})
+
+	It("should tolerate the reader disconnecting and reconnecting", func() {
+		reader := openReader()
+		Expect(pipe.Write([]byte("first"))).To(Equal(len("first")))
+		expectRead(reader, "first")
+		Expect(reader.Close()).To(Succeed())
+
+		Expect(pipe.Write([]byte("broken"))).To(Equal(len("broken")))
+
+		reader = openReader()
+		defer reader.Close()
+		Expect(pipe.Write([]byte("second"))).To(Equal(len("second")))
+		expectRead(reader, "second")
+	})
+
+	It("should copy the console output to stdout and the pipe", func() {
+		reader := openReader()
+		defer reader.Close()
+		out := &recordingWriter{}
+
+		Expect(handleOutputCopy(out, strings.NewReader("console output"), attachOptions{outputPipe: pipe})).To(Succeed())
+		Expect(out.String()).To(Equal("console output"))
+		expectRead(reader, "console output")
+	})
+
+	It("should reject a path that is not a named pipe", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "file")
+		Expect(os.WriteFile(file, nil, 0o600)).To(Succeed())
+
+		_, err := newOutputPipe(file)
+		Expect(err).To(MatchError(ContainSubstring("is not a named pipe")))
+	})
+})
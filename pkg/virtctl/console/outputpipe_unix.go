@@ -0,0 +1,99 @@
+//go:build !windows
+
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+
+package console
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// outputPipe writes the output of the console to a named pipe read by external tooling.
+// Writes never fail so that the interactive session is unaffected by the reader of the pipe:
+// output is dropped while no reader is connected or the reader falls behind, and the pipe is
+// reopened once a reader connects again after disconnecting.
+type outputPipe struct {
+	lock sync.Mutex
+	path string
+	// fd is the non-blocking write end of the pipe, or -1 while no reader is connected
+	fd int
+}
+
+func newOutputPipe(path string) (io.WriteCloser, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot use output pipe: %v", err)
+	}
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		return nil, fmt.Errorf("cannot use output pipe: %s is not a named pipe", path)
+	}
+	return &outputPipe{path: path, fd: -1}, nil
+}
+
+func (p *outputPipe) Write(b []byte) (int, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.fd < 0 {
+		// Opening the write end of a pipe without blocking fails with ENXIO until a reader connects
+		fd, err := syscall.Open(p.path, syscall.O_WRONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+		if err != nil {
+			return len(b), nil
+		}
+		p.fd = fd
+	}
+
+	for written := 0; written < len(b); {
+		n, err := syscall.Write(p.fd, b[written:])
+		if err == syscall.EINTR {
+			continue
+		}
+		if err == syscall.EAGAIN {
+			// The reader is falling behind, drop the remaining output
+			break
+		}
+		if err != nil {
+			// The reader disconnected, reopen the pipe once another reader connects
+			_ = p.closeLocked()
+			break
+		}
+		written += n
+	}
+	return len(b), nil
+}
+
+func (p *outputPipe) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.closeLocked()
+}
+
+func (p *outputPipe) closeLocked() error {
+	if p.fd < 0 {
+		return nil
+	}
+	err := syscall.Close(p.fd)
+	p.fd = -1
+	return err
+}
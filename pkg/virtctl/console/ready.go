@@ -0,0 +1,93 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright The KubeVirt Authors.
+ *
+ */
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	v1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+
+	virtwait "kubevirt.io/kubevirt/pkg/apimachinery/wait"
+)
+
+const (
+	readyPollInterval      = time.Second
+	poweredOffPollInterval = 500 * time.Millisecond
+	poweredOffTimeout      = 3 * time.Second
+)
+
+// waitForVMIRunning waits up to timeout for the VMI to reach the running phase before connecting to its console,
+// reporting each phase the VMI passes through. A VMI that does not exist yet, such as that of a VM that is
+// still starting, is waited for, while a VMI that has already finished returns an error immediately.
+// Users only allowed to access the console may not be allowed to get the VMI, in which case the wait is skipped.
+func waitForVMIRunning(client kubecli.KubevirtClient, namespace, name string, interval, timeout time.Duration) error {
+	var lastPhase v1.VirtualMachineInstancePhase
+	err := virtwait.PollImmediately(interval, timeout, func(ctx context.Context) (bool, error) {
+		vmi, err := client.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if errors.IsForbidden(err) || errors.IsUnauthorized(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		switch {
+		case vmi.Status.Phase == v1.Running:
+			return true, nil
+		case vmi.IsFinal():
+			return false, fmt.Errorf("virtual machine instance %s has already finished in phase %s", name, vmi.Status.Phase)
+		case vmi.Status.Phase != lastPhase:
+			lastPhase = vmi.Status.Phase
+			printNotice(noticeColorNone, fmt.Sprintf("Waiting for %s to be running, current phase: %s\n", name, lastPhase))
+		}
+		return false, nil
+	})
+	if wait.Interrupted(err) {
+		return fmt.Errorf("%w waiting for virtual machine instance %s to be running", errTimeout, name)
+	}
+	return err
+}
+
+// waitForVMIPoweredOff reports whether the VMI was powered off once its console was disconnected, waiting up to timeout
+// for the VMI to be removed or to finish as its status may not have caught up yet. The server does not send the reason
+// for closing the console connection, so the VMI itself is the only way of telling a power-off apart.
+func waitForVMIPoweredOff(client kubecli.KubevirtClient, namespace, name string, interval, timeout time.Duration) bool {
+	err := virtwait.PollImmediately(interval, timeout, func(ctx context.Context) (bool, error) {
+		vmi, err := client.VirtualMachineInstance(namespace).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return vmi.IsFinal() || vmi.IsMarkedForDeletion(), nil
+	})
+	return err == nil
+}
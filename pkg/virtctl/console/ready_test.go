@@ -0,0 +1,190 @@
+package console
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/testing"
+
+	v1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/api"
+	"kubevirt.io/client-go/kubecli"
+	kubevirtfake "kubevirt.io/client-go/kubevirt/fake"
+
+	"kubevirt.io/kubevirt/pkg/pointer"
+)
+
+var _ = Describe("waitForVMIRunning", func() {
+	const (
+		vmiName  = "testvmi"
+		interval = time.Millisecond
+		timeout  = time.Second
+	)
+
+	var (
+		client     *kubecli.MockKubevirtClient
+		virtClient *kubevirtfake.Clientset
+	)
+
+	BeforeEach(func() {
+		virtClient = kubevirtfake.NewSimpleClientset()
+		client = kubecli.NewMockKubevirtClient(gomock.NewController(GinkgoT()))
+		client.EXPECT().VirtualMachineInstance(metav1.NamespaceDefault).
+			Return(virtClient.KubevirtV1().VirtualMachineInstances(metav1.NamespaceDefault)).AnyTimes()
+	})
+
+	// createVMI adds the VMI to the tracker directly so that it can also be called from within reactors
+	createVMI := func(phase v1.VirtualMachineInstancePhase) {
+		vmi := api.NewMinimalVMI(vmiName)
+		vmi.Namespace = metav1.NamespaceDefault
+		vmi.Status.Phase = phase
+		Expect(virtClient.Tracker().Add(vmi)).To(Succeed())
+	}
+
+	// transitionToRunning moves the VMI to the running phase once it has been retrieved the given number of times
+	transitionToRunning := func(gets int) {
+		virtClient.PrependReactor("get", "virtualmachineinstances", func(testing.Action) (bool, runtime.Object, error) {
+			gets--
+			if gets > 0 {
+				return false, nil, nil
+			}
+			vmi, err := virtClient.Tracker().Get(v1.SchemeGroupVersion.WithResource("virtualmachineinstances"), metav1.NamespaceDefault, vmiName)
+			Expect(err).ToNot(HaveOccurred())
+			vmi.(*v1.VirtualMachineInstance).Status.Phase = v1.Running
+			return true, vmi, nil
+		})
+	}
+
+	It("should return once the VMI is running", func() {
+		createVMI(v1.Running)
+		Expect(waitForVMIRunning(client, metav1.NamespaceDefault, vmiName, interval, timeout)).To(Succeed())
+	})
+
+	It("should wait for the VMI to transition to running", func() {
+		createVMI(v1.Scheduling)
+		transitionToRunning(3)
+		Expect(waitForVMIRunning(client, metav1.NamespaceDefault, vmiName, interval, timeout)).To(Succeed())
+	})
+
+	It("should wait for the VMI to be created", func() {
+		transitionToRunning(3)
+		virtClient.PrependReactor("get", "virtualmachineinstances", func(testing.Action) (bool, runtime.Object, error) {
+			if _, err := virtClient.Tracker().Get(
+				v1.SchemeGroupVersion.WithResource("virtualmachineinstances"), metav1.NamespaceDefault, vmiName,
+			); err != nil {
+				createVMI(v1.Pending)
+			}
+			return false, nil, nil
+		})
+		Expect(waitForVMIRunning(client, metav1.NamespaceDefault, vmiName, interval, timeout)).To(Succeed())
+	})
+
+	It("should time out when the VMI does not become running", func() {
+		createVMI(v1.Scheduling)
+		err := waitForVMIRunning(client, metav1.NamespaceDefault, vmiName, interval, 10*time.Millisecond)
+		Expect(err).To(MatchError("timeout waiting for virtual machine instance testvmi to be running"))
+		Expect(err).To(MatchError(errTimeout))
+	})
+
+	DescribeTable("should fail immediately when the VMI has finished", func(phase v1.VirtualMachineInstancePhase) {
+		createVMI(phase)
+		Expect(waitForVMIRunning(client, metav1.NamespaceDefault, vmiName, interval, timeout)).To(
+			MatchError("virtual machine instance testvmi has already finished in phase " + string(phase)))
+	},
+		Entry("as succeeded", v1.Succeeded),
+		Entry("as failed", v1.Failed),
+	)
+
+	It("should return errors retrieving the VMI", func() {
+		virtClient.PrependReactor("get", "virtualmachineinstances", func(testing.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("failure")
+		})
+		Expect(waitForVMIRunning(client, metav1.NamespaceDefault, vmiName, interval, timeout)).To(MatchError("failure"))
+	})
+
+	DescribeTable("should skip waiting when not allowed to get the VMI", func(err error) {
+		virtClient.PrependReactor("get", "virtualmachineinstances", func(testing.Action) (bool, runtime.Object, error) {
+			return true, nil, err
+		})
+		Expect(waitForVMIRunning(client, metav1.NamespaceDefault, vmiName, interval, timeout)).To(Succeed())
+	},
+		Entry("when forbidden", k8serrors.NewForbidden(v1.Resource("virtualmachineinstances"), vmiName, errors.New("denied by RBAC"))),
+		Entry("when unauthorized", k8serrors.NewUnauthorized("unauthorized")),
+	)
+})
+
+var _ = Describe("waitForVMIPoweredOff", func() {
+	const (
+		vmiName  = "testvmi"
+		interval = time.Millisecond
+		timeout  = 10 * time.Millisecond
+	)
+
+	var (
+		client     *kubecli.MockKubevirtClient
+		virtClient *kubevirtfake.Clientset
+	)
+
+	BeforeEach(func() {
+		virtClient = kubevirtfake.NewSimpleClientset()
+		client = kubecli.NewMockKubevirtClient(gomock.NewController(GinkgoT()))
+		client.EXPECT().VirtualMachineInstance(metav1.NamespaceDefault).
+			Return(virtClient.KubevirtV1().VirtualMachineInstances(metav1.NamespaceDefault)).AnyTimes()
+	})
+
+	createVMI := func(phase v1.VirtualMachineInstancePhase, deletionTimestamp *metav1.Time) {
+		vmi := api.NewMinimalVMI(vmiName)
+		vmi.Namespace = metav1.NamespaceDefault
+		vmi.Status.Phase = phase
+		vmi.DeletionTimestamp = deletionTimestamp
+		Expect(virtClient.Tracker().Add(vmi)).To(Succeed())
+	}
+
+	It("should report a VMI that no longer exists as powered off", func() {
+		Expect(waitForVMIPoweredOff(client, metav1.NamespaceDefault, vmiName, interval, timeout)).To(BeTrue())
+	})
+
+	DescribeTable("should report a VMI as powered off", func(phase v1.VirtualMachineInstancePhase, deletionTimestamp *metav1.Time) {
+		createVMI(phase, deletionTimestamp)
+		Expect(waitForVMIPoweredOff(client, metav1.NamespaceDefault, vmiName, interval, timeout)).To(BeTrue())
+	},
+		Entry("once it succeeded", v1.Succeeded, nil),
+		Entry("once it failed", v1.Failed, nil),
+		Entry("while it is being deleted", v1.Running, pointer.P(metav1.Now())),
+	)
+
+	It("should wait for the status of the VMI to catch up", func() {
+		createVMI(v1.Running, nil)
+		gets := 3
+		virtClient.PrependReactor("get", "virtualmachineinstances", func(testing.Action) (bool, runtime.Object, error) {
+			gets--
+			if gets > 0 {
+				return false, nil, nil
+			}
+			vmi, err := virtClient.Tracker().Get(v1.SchemeGroupVersion.WithResource("virtualmachineinstances"), metav1.NamespaceDefault, vmiName)
+			Expect(err).ToNot(HaveOccurred())
+			vmi.(*v1.VirtualMachineInstance).Status.Phase = v1.Succeeded
+			return true, vmi, nil
+		})
+		Expect(waitForVMIPoweredOff(client, metav1.NamespaceDefault, vmiName, interval, time.Second)).To(BeTrue())
+	})
+
+	It("should not report a VMI that is still running as powered off", func() {
+		createVMI(v1.Running, nil)
+		Expect(waitForVMIPoweredOff(client, metav1.NamespaceDefault, vmiName, interval, timeout)).To(BeFalse())
+	})
+
+	It("should not report a VMI as powered off when it can not be retrieved", func() {
+		virtClient.PrependReactor("get", "virtualmachineinstances", func(testing.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("failure")
+		})
+		Expect(waitForVMIPoweredOff(client, metav1.NamespaceDefault, vmiName, interval, timeout)).To(BeFalse())
+	})
+})
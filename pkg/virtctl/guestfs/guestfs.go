@@ -2,6 +2,7 @@ package guestfs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -560,8 +561,12 @@ func CreateAttacher(client *K8sClient, p *corev1.Pod, command string) error {
 			Stderr: stdoutWriter,
 		})
 	}()
-	return console.Attach(stdinReader, stdoutReader, stdinWriter, stdoutWriter,
+	err = console.Attach(stdinReader, stdoutReader, stdinWriter, stdoutWriter,
 		"If you don't see a command prompt, try pressing enter.", resChan)
+	if errors.Is(err, console.ErrUserDetached) {
+		return nil
+	}
+	return err
 }
 
 func (c *guestfsCommand) createInteractivePodWithPVC(client *K8sClient, ns, command string, args []string, isblock bool) error {
@@ -2,6 +2,7 @@ package virtctl
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -157,6 +158,10 @@ func Execute() int {
 			cmd.PrintErrln(versionErr)
 		}
 		cmd.PrintErrln(err)
+		var exitErr *console.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.Code
+		}
 		return 1
 	}
 	return 0
@@ -20,6 +20,7 @@ import (
 
 	"kubevirt.io/kubevirt/pkg/virtctl"
 	"kubevirt.io/kubevirt/pkg/virtctl/clientconfig"
+	"kubevirt.io/kubevirt/pkg/virtctl/console"
 	"kubevirt.io/kubevirt/pkg/virtctl/testing"
 )
 
@@ -80,4 +81,35 @@ var _ = Describe("virtctl", func() {
 		Expect(out.String()).To(ContainSubstring(testError))
 		Expect(out.String()).To(ContainSubstring("You are using a client virtctl version that is different from the KubeVirt version running in the cluster"))
 	})
+
+	It("Execute should return the exit code of a console exit error", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		serverVersionInterface := kubecli.NewMockServerVersionInterface(ctrl)
+		serverVersionInterface.EXPECT().Get().Return(nil, errors.New("server version unavailable")).AnyTimes()
+		kubecli.GetKubevirtClientFromClientConfig = kubecli.GetMockKubevirtClientFromClientConfig
+		kubecli.MockKubevirtClientInstance = kubecli.NewMockKubevirtClient(ctrl)
+		kubecli.MockKubevirtClientInstance.EXPECT().ServerVersion().Return(serverVersionInterface).AnyTimes()
+
+		const testError = "testError"
+		cmd := &cobra.Command{
+			RunE: func(_ *cobra.Command, _ []string) error {
+				return &console.ExitError{Err: errors.New(testError), Code: console.ExitCodePowerOff}
+			},
+		}
+		out := &bytes.Buffer{}
+		cmd.SetErr(out)
+		cmd.SetContext(clientconfig.NewContext(
+			context.Background(), kubecli.DefaultClientConfig(&pflag.FlagSet{}),
+		))
+
+		virtctl.NewVirtctlCommand = func() *cobra.Command {
+			return cmd
+		}
+		DeferCleanup(func() {
+			virtctl.NewVirtctlCommand = virtctl.NewVirtctlCommandFn
+		})
+
+		Expect(virtctl.Execute()).To(Equal(console.ExitCodePowerOff))
+		Expect(out.String()).To(ContainSubstring(testError))
+	})
 })
@@ -3,6 +3,7 @@ package templates
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -55,12 +56,17 @@ func OptionsUsageTemplate() string {
 
 // PrintWarningForPausedVMI prints warning message if VMI is paused
 func PrintWarningForPausedVMI(virtCli kubecli.KubevirtClient, vmiName string, namespace string) {
+	FprintWarningForPausedVMI(os.Stderr, virtCli, vmiName, namespace)
+}
+
+// FprintWarningForPausedVMI writes a warning message to w if VMI is paused
+func FprintWarningForPausedVMI(w io.Writer, virtCli kubecli.KubevirtClient, vmiName string, namespace string) {
 	vmi, err := virtCli.VirtualMachineInstance(namespace).Get(context.Background(), vmiName, k8smetav1.GetOptions{})
 	if err != nil {
 		return
 	}
 	condManager := controller.NewVirtualMachineInstanceConditionManager()
 	if condManager.HasCondition(vmi, v1.VirtualMachineInstancePaused) {
-		fmt.Fprintf(os.Stderr, "\rWarning: %s is paused. Console will be active after unpause.\n", vmiName)
+		fmt.Fprintf(w, "\rWarning: %s is paused. Console will be active after unpause.\n", vmiName)
 	}
 }
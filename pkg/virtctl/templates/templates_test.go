@@ -0,0 +1,66 @@
+package templates_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	k8sv1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/api"
+	"kubevirt.io/client-go/kubecli"
+
+	"kubevirt.io/kubevirt/pkg/virtctl/templates"
+)
+
+var _ = Describe("FprintWarningForPausedVMI", func() {
+	const vmiName = "testvmi"
+
+	var (
+		virtClient   *kubecli.MockKubevirtClient
+		vmiInterface *kubecli.MockVirtualMachineInstanceInterface
+		out          *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		virtClient = kubecli.NewMockKubevirtClient(ctrl)
+		vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		virtClient.EXPECT().VirtualMachineInstance(k8smetav1.NamespaceDefault).Return(vmiInterface)
+		out = &bytes.Buffer{}
+	})
+
+	It("should print a warning for a paused VMI", func() {
+		vmi := api.NewMinimalVMI(vmiName)
+		vmi.Status.Conditions = []v1.VirtualMachineInstanceCondition{{
+			Type:   v1.VirtualMachineInstancePaused,
+			Status: k8sv1.ConditionTrue,
+		}}
+		vmiInterface.EXPECT().Get(context.Background(), vmiName, k8smetav1.GetOptions{}).Return(vmi, nil)
+
+		templates.FprintWarningForPausedVMI(out, virtClient, vmiName, k8smetav1.NamespaceDefault)
+		Expect(out.String()).To(Equal("\rWarning: testvmi is paused. Console will be active after unpause.\n"))
+	})
+
+	It("should not print a warning for a running VMI", func() {
+		vmi := api.NewMinimalVMI(vmiName)
+		vmi.Status.Phase = v1.Running
+		vmiInterface.EXPECT().Get(context.Background(), vmiName, k8smetav1.GetOptions{}).Return(vmi, nil)
+
+		templates.FprintWarningForPausedVMI(out, virtClient, vmiName, k8smetav1.NamespaceDefault)
+		Expect(out.String()).To(BeEmpty())
+	})
+
+	It("should not print a warning when the VMI cannot be retrieved", func() {
+		vmiInterface.EXPECT().Get(context.Background(), vmiName, k8smetav1.GetOptions{}).Return(nil, errors.New("failure"))
+
+		templates.FprintWarningForPausedVMI(out, virtClient, vmiName, k8smetav1.NamespaceDefault)
+		Expect(out.String()).To(BeEmpty())
+	})
+})
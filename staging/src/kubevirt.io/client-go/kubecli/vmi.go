@@ -108,7 +108,7 @@ func (v *vmis) SerialConsole(name string, options *kvcorev1.SerialConsoleOptions
 				default:
 				}
 
-				con, err := kvcorev1.AsyncSubresourceHelper(v.config, v.resource, v.namespace, name, "console", url.Values{})
+				con, err := kvcorev1.AsyncSubresourceHelperWithDialer(v.config, options.Dialer, v.resource, v.namespace, name, "console", url.Values{})
 				if err != nil {
 					asyncSubresourceError, ok := err.(*kvcorev1.AsyncSubresourceError)
 					// return if response status code does not equal to 400
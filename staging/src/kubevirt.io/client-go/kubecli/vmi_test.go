@@ -23,6 +23,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"path"
 	"time"
@@ -191,6 +192,37 @@ var _ = Describe("Kubevirt VirtualMachineInstance Client", func() {
 		Entry("with proxied server URL", proxyPath),
 	)
 
+	DescribeTable("should connect to the serial console using a custom websocket dialer", func(proxyPath string) {
+		client, err := GetKubevirtClientFromFlags(server.URL()+proxyPath, "")
+		Expect(err).ToNot(HaveOccurred())
+
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", path.Join(proxyPath, subVMIPath, "console")),
+			func(w http.ResponseWriter, r *http.Request) {
+				_, err := upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+			},
+		))
+
+		var dialedAddr string
+		dialer := &websocket.Dialer{
+			NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dialedAddr = addr
+				return (&net.Dialer{}).DialContext(ctx, network, addr)
+			},
+		}
+		_, err = client.VirtualMachineInstance(k8sv1.NamespaceDefault).SerialConsole(
+			"testvm", &kvcorev1.SerialConsoleOptions{ConnectionTimeout: 10 * time.Second, Dialer: dialer},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dialedAddr).To(Equal(server.Addr()))
+	},
+		Entry("with regular server URL", ""),
+		Entry("with proxied server URL", proxyPath),
+	)
+
 	DescribeTable("should handle a failure connecting to the VM", func(proxyPath string) {
 		client, err := GetKubevirtClientFromFlags(server.URL()+proxyPath, "")
 		Expect(err).ToNot(HaveOccurred())
@@ -43,6 +43,14 @@ func (a *AsyncSubresourceError) GetStatusCode() int {
 
 // params are strings with "key=value" format
 func AsyncSubresourceHelper(config *rest.Config, resource, namespace, name string, subresource string, queryParams url.Values) (StreamInterface, error) {
+	return AsyncSubresourceHelperWithDialer(config, nil, resource, namespace, name, subresource, queryParams)
+}
+
+// AsyncSubresourceHelperWithDialer connects to the subresource using a copy of the provided websocket dialer,
+// see WebsocketDialerFor for how it is combined with the config. A nil dialer behaves like AsyncSubresourceHelper.
+func AsyncSubresourceHelperWithDialer(
+	config *rest.Config, dialer *websocket.Dialer, resource, namespace, name string, subresource string, queryParams url.Values,
+) (StreamInterface, error) {
 
 	done := make(chan struct{})
 
@@ -51,7 +59,7 @@ func AsyncSubresourceHelper(config *rest.Config, resource, namespace, name strin
 		Done:       done,
 	}
 	// Create a round tripper with all necessary kubernetes security details
-	wrappedRoundTripper, err := roundTripperFromConfig(config, aws.WebsocketCallback)
+	wrappedRoundTripper, err := roundTripperFromConfig(config, dialer, aws.WebsocketCallback)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create round tripper for remote execution: %v", err)
 	}
@@ -139,27 +147,12 @@ func (aws *AsyncWSRoundTripper) WebsocketCallback(ws *websocket.Conn, resp *http
 	return nil
 }
 
-func roundTripperFromConfig(config *rest.Config, callback RoundTripCallback) (http.RoundTripper, error) {
-
-	// Configure TLS
-	tlsConfig, err := rest.TLSConfigFor(config)
+func roundTripperFromConfig(config *rest.Config, customDialer *websocket.Dialer, callback RoundTripCallback) (http.RoundTripper, error) {
+	dialer, err := WebsocketDialerFor(config, customDialer)
 	if err != nil {
 		return nil, err
 	}
 
-	// Configure the websocket dialer
-	proxy := http.ProxyFromEnvironment
-	if config.Proxy != nil {
-		proxy = config.Proxy
-	}
-	dialer := &websocket.Dialer{
-		Proxy:           proxy,
-		TLSClientConfig: tlsConfig,
-		WriteBufferSize: WebsocketMessageBufferSize,
-		ReadBufferSize:  WebsocketMessageBufferSize,
-		Subprotocols:    []string{subresources.PlainStreamProtocolName},
-	}
-
 	// Create a roundtripper which will pass in the final underlying websocket connection to a callback
 	rt := &WebsocketRoundTripper{
 		Do:     callback,
@@ -170,6 +163,45 @@ func roundTripperFromConfig(config *rest.Config, callback RoundTripCallback) (ht
 	return rest.HTTPWrappersForConfig(config, rt)
 }
 
+// WebsocketDialerFor returns the websocket dialer used to connect to subresources.
+// Settings of the custom dialer take precedence over those derived from the config: the proxy and
+// TLS client config of the config, or http.ProxyFromEnvironment when the config has no proxy, are only
+// used when left nil on the custom dialer. Authentication headers of the config are always applied.
+func WebsocketDialerFor(config *rest.Config, customDialer *websocket.Dialer) (*websocket.Dialer, error) {
+	dialer := &websocket.Dialer{}
+	if customDialer != nil {
+		*dialer = *customDialer
+	}
+
+	// Configure TLS
+	if dialer.TLSClientConfig == nil {
+		tlsConfig, err := rest.TLSConfigFor(config)
+		if err != nil {
+			return nil, err
+		}
+		dialer.TLSClientConfig = tlsConfig
+	}
+
+	// Configure the proxy
+	if dialer.Proxy == nil {
+		dialer.Proxy = http.ProxyFromEnvironment
+		if config.Proxy != nil {
+			dialer.Proxy = config.Proxy
+		}
+	}
+
+	if dialer.WriteBufferSize == 0 {
+		dialer.WriteBufferSize = WebsocketMessageBufferSize
+	}
+	if dialer.ReadBufferSize == 0 {
+		dialer.ReadBufferSize = WebsocketMessageBufferSize
+	}
+	if len(dialer.Subprotocols) == 0 {
+		dialer.Subprotocols = []string{subresources.PlainStreamProtocolName}
+	}
+	return dialer, nil
+}
+
 func RequestFromConfig(config *rest.Config, resource, name, namespace, subresource string, queryParams url.Values) (*http.Request, error) {
 
 	u, err := url.Parse(config.Host)
@@ -25,6 +25,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/gorilla/websocket"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "kubevirt.io/api/core/v1"
@@ -35,6 +37,9 @@ const vmiSubresourceURL = "/apis/subresources.kubevirt.io/%s"
 
 type SerialConsoleOptions struct {
 	ConnectionTimeout time.Duration
+	// Dialer optionally provides the websocket dialer used to connect to the console, for example to route
+	// the connection through an authenticating proxy. See WebsocketDialerFor for its precedence over the config.
+	Dialer *websocket.Dialer
 }
 
 type VirtualMachineInstanceExpansion interface {